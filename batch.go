@@ -0,0 +1,140 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Batch is a store reading from an underlying store and collecting writes to it, which are applied by [Batch.Apply].
+// Store has no rollback, so a batch lets an update write nothing when it fails halfway.
+// Reads don't see collected writes, so an update must do all reads before the first write.
+type Batch[K, V any] struct {
+	store  Store[K, V]
+	writes []batchWrite[K, V]
+}
+
+type batchWrite[K, V any] struct {
+	key    K
+	value  V
+	ttl    time.Duration
+	delete bool
+}
+
+// NewBatch creates a batch over the store.
+func NewBatch[K, V any](store Store[K, V]) *Batch[K, V] {
+	return &Batch[K, V]{store: store}
+}
+
+var _ Store[string, string] = (*Batch[string, string])(nil)
+var _ StoreTTL[string, string] = (*Batch[string, string])(nil)
+
+// Get implements Store.
+func (b *Batch[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return b.store.Get(ctx, k)
+}
+
+// Range implements Store.
+func (b *Batch[K, V]) Range(ctx context.Context, iter Iter[K, V]) error {
+	return b.store.Range(ctx, iter)
+}
+
+// RangeWithPrefix implements Store.
+func (b *Batch[K, V]) RangeWithPrefix(ctx context.Context, k K, iter Iter[K, V]) error {
+	return b.store.RangeWithPrefix(ctx, k, iter)
+}
+
+// Set implements Store.
+func (b *Batch[K, V]) Set(ctx context.Context, k K, v V) error {
+	b.writes = append(b.writes, batchWrite[K, V]{key: k, value: v})
+	return nil
+}
+
+// SetWithTTL implements StoreTTL, the ttl is passed to the underlying store by [SetWithTTL].
+func (b *Batch[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	b.writes = append(b.writes, batchWrite[K, V]{key: k, value: v, ttl: ttl})
+	return nil
+}
+
+// Edit implements Store.
+func (b *Batch[K, V]) Edit(ctx context.Context, k K, edit Edit[V]) error {
+	v, err := b.store.Get(ctx, k)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return b.Set(ctx, k, v)
+}
+
+// Delete implements Store.
+func (b *Batch[K, V]) Delete(ctx context.Context, k K) error {
+	b.writes = append(b.writes, batchWrite[K, V]{key: k, delete: true})
+	return nil
+}
+
+// Close implements Store, it discards collected writes and doesn't close the underlying store.
+func (b *Batch[K, V]) Close(ctx context.Context) error {
+	b.writes = nil
+	return nil
+}
+
+// Apply writes collected writes to the underlying store in order.
+// Only a write rejected by the store itself may leave the preceding writes applied.
+func (b *Batch[K, V]) Apply(ctx context.Context) error {
+	writes := b.writes
+	b.writes = nil
+	for _, w := range writes {
+		var err error
+		switch {
+		case w.delete:
+			err = b.store.Delete(ctx, w.key)
+		case w.ttl > 0:
+			err = SetWithTTL(ctx, b.store, w.key, w.value, w.ttl)
+		default:
+			err = b.store.Set(ctx, w.key, w.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update runs the function on a [Batch] and applies its writes only if the function succeeds.
+// If the store implements [TransactionalStore], the batch reads from an update transaction,
+// which is retried on [ErrConflict]. Otherwise the batch works on the store itself,
+// and the caller must serialize concurrent updates.
+func Update[K, V any](ctx context.Context, store Store[K, V], f func(tx Store[K, V]) error) error {
+	ts, ok := store.(TransactionalStore[K, V])
+	if !ok {
+		b := NewBatch(store)
+		err := f(b)
+		if err != nil {
+			return err
+		}
+		return b.Apply(ctx)
+	}
+
+	for {
+		tx, err := ts.Transaction(true)
+		if err != nil {
+			return err
+		}
+
+		b := NewBatch(tx)
+		err = f(b)
+		if err == nil {
+			err = b.Apply(ctx)
+		}
+		err = errors.Join(err, tx.Close(ctx))
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package kv
+
+import (
+	"context"
+	"errors"
+)
+
+// StoreHas is an optional interface for stores able to check key existence without reading and decoding the value.
+type StoreHas[K any] interface {
+	// Has reports whether a value is stored for the given key.
+	Has(ctx context.Context, k K) (bool, error)
+}
+
+// StoreCount is an optional interface for stores able to count keys natively.
+type StoreCount[K any] interface {
+	// Count returns the number of keys with the given prefix.
+	// Zero value prefix counts all keys in the store.
+	Count(ctx context.Context, prefix K) (int, error)
+}
+
+// Has reports whether a value is stored for the given key.
+// It uses [StoreHas] when the store implements it and falls back to [Store.Get] otherwise.
+func Has[K, V any](ctx context.Context, s Store[K, V], k K) (bool, error) {
+	if sh, ok := s.(StoreHas[K]); ok {
+		return sh.Has(ctx, k)
+	}
+
+	_, err := s.Get(ctx, k)
+	if errors.Is(err, ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Count returns the number of keys with the given prefix.
+// It uses [StoreCount] when the store implements it and falls back to [RangeKeysWithPrefix] otherwise.
+func Count[K, V any](ctx context.Context, s Store[K, V], prefix K) (int, error) {
+	if sc, ok := s.(StoreCount[K]); ok {
+		return sc.Count(ctx, prefix)
+	}
+
+	n := 0
+	err := RangeKeysWithPrefix(ctx, s, prefix, func(K) error {
+		n++
+		return nil
+	})
+	return n, err
+}
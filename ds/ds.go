@@ -0,0 +1,19 @@
+// Package ds implements Redis-like containers on top of any key-value store.
+//
+// Every element of a container is stored as an individual key under a prefix of the container kind and name,
+// so huge containers never require rewriting a single giant value.
+package ds
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrEmpty is returned when popping from an empty list.
+var ErrEmpty = errors.New("list is empty")
+
+// namespace returns the key prefix of a container, tagged with the container kind,
+// the name is length-prefixed so a namespace is never a prefix of another one.
+func namespace(kind, name string) string {
+	return kind + "/" + strconv.Itoa(len(name)) + ":" + name + "/"
+}
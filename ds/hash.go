@@ -0,0 +1,73 @@
+package ds
+
+import (
+	"context"
+
+	"github.com/royalcat/kv"
+)
+
+// Hash is a map of fields to values stored under a common prefix.
+type Hash[V any] struct {
+	fields kv.Store[string, []byte]
+	codec  kv.Codec[V]
+}
+
+// NewHash creates a hash with the given name in the store, values are encoded with the codec.
+func NewHash[V any](store kv.Store[string, []byte], name string, codec kv.Codec[V]) *Hash[V] {
+	return &Hash[V]{
+		fields: kv.PrefixBytes(store, namespace("hash", name)),
+		codec:  codec,
+	}
+}
+
+// HSet sets the value of the field.
+func (h *Hash[V]) HSet(ctx context.Context, field string, v V) error {
+	data, err := h.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return h.fields.Set(ctx, field, data)
+}
+
+// HGet returns the value of the field or kv.ErrKeyNotFound.
+func (h *Hash[V]) HGet(ctx context.Context, field string) (V, error) {
+	var v V
+	data, err := h.fields.Get(ctx, field)
+	if err != nil {
+		return v, err
+	}
+	err = h.codec.Unmarshal(data, &v)
+	return v, err
+}
+
+// HDel deletes the field, deleting a missing field is not an error.
+func (h *Hash[V]) HDel(ctx context.Context, field string) error {
+	return h.fields.Delete(ctx, field)
+}
+
+// HExists reports whether the field is set.
+func (h *Hash[V]) HExists(ctx context.Context, field string) (bool, error) {
+	return kv.Has(ctx, h.fields, field)
+}
+
+// HLen returns the number of fields.
+func (h *Hash[V]) HLen(ctx context.Context) (int, error) {
+	return kv.Count(ctx, h.fields, "")
+}
+
+// HKeys iterates over the fields without reading values.
+func (h *Hash[V]) HKeys(ctx context.Context, iter kv.KeyIter[string]) error {
+	return kv.RangeKeys(ctx, h.fields, iter)
+}
+
+// HRange iterates over the fields and values.
+func (h *Hash[V]) HRange(ctx context.Context, iter kv.Iter[string, V]) error {
+	return h.fields.Range(ctx, func(field string, data []byte) error {
+		var v V
+		err := h.codec.Unmarshal(data, &v)
+		if err != nil {
+			return err
+		}
+		return iter(field, v)
+	})
+}
@@ -0,0 +1,248 @@
+package ds
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/royalcat/kv"
+)
+
+var errInvalidListMeta = errors.New("invalid list metadata")
+
+// List is a double-ended list of values, every element is stored under its own position key.
+//
+// Positions of the head and the tail are kept in a separate metadata key.
+// If the store implements kv.TransactionalStore, every push and pop is a single transaction,
+// otherwise operations are only serialized within the list instance.
+type List[V any] struct {
+	mu       sync.Mutex
+	store    kv.Store[string, []byte]
+	meta     string
+	elements string
+	codec    kv.Codec[V]
+}
+
+// NewList creates a list with the given name in the store, values are encoded with the codec.
+func NewList[V any](store kv.Store[string, []byte], name string, codec kv.Codec[V]) *List[V] {
+	ns := namespace("list", name)
+	return &List[V]{
+		store:    store,
+		meta:     ns + "meta",
+		elements: ns + "e/",
+		codec:    codec,
+	}
+}
+
+// LPush inserts the values at the head of the list, the last value becomes the new head.
+func (l *List[V]) LPush(ctx context.Context, values ...V) error {
+	return l.push(ctx, values, true)
+}
+
+// RPush appends the values at the tail of the list.
+func (l *List[V]) RPush(ctx context.Context, values ...V) error {
+	return l.push(ctx, values, false)
+}
+
+// LPop removes and returns the head of the list or ErrEmpty.
+func (l *List[V]) LPop(ctx context.Context) (V, error) {
+	return l.pop(ctx, true)
+}
+
+// RPop removes and returns the tail of the list or ErrEmpty.
+func (l *List[V]) RPop(ctx context.Context) (V, error) {
+	return l.pop(ctx, false)
+}
+
+// LLen returns the number of elements in the list.
+func (l *List[V]) LLen(ctx context.Context) (int, error) {
+	m, err := l.getMeta(ctx, l.store)
+	if err != nil {
+		return 0, err
+	}
+	return m.len(), nil
+}
+
+// LIndex returns the element at the index, negative indexes count from the tail, -1 is the last element.
+// It returns kv.ErrKeyNotFound when the index is out of range.
+func (l *List[V]) LIndex(ctx context.Context, index int) (V, error) {
+	var v V
+	m, err := l.getMeta(ctx, l.store)
+	if err != nil {
+		return v, err
+	}
+
+	if index < 0 {
+		index += m.len()
+	}
+	if index < 0 || index >= m.len() {
+		return v, kv.ErrKeyNotFound
+	}
+
+	return l.get(ctx, l.store, m.head+int64(index))
+}
+
+// LRange iterates over the elements from start to stop inclusive, negative indexes count from the tail.
+// Out of range indexes are clamped to the list bounds.
+func (l *List[V]) LRange(ctx context.Context, start, stop int, iter func(index int, v V) error) error {
+	m, err := l.getMeta(ctx, l.store)
+	if err != nil {
+		return err
+	}
+
+	n := m.len()
+	if start < 0 {
+		start = max(start+n, 0)
+	}
+	if stop < 0 {
+		stop += n
+	}
+	stop = min(stop, n-1)
+
+	for i := start; i <= stop; i++ {
+		v, err := l.get(ctx, l.store, m.head+int64(i))
+		if err != nil {
+			return err
+		}
+		err = iter(i, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *List[V]) push(ctx context.Context, values []V, head bool) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	data := make([][]byte, 0, len(values))
+	for _, v := range values {
+		d, err := l.codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = append(data, d)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return kv.Update(ctx, l.store, func(s kv.Store[string, []byte]) error {
+		m, err := l.getMeta(ctx, s)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range data {
+			var pos int64
+			if head {
+				m.head--
+				pos = m.head
+			} else {
+				pos = m.tail
+				m.tail++
+			}
+
+			err = s.Set(ctx, l.elementKey(pos), d)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.Set(ctx, l.meta, m.marshal())
+	})
+}
+
+func (l *List[V]) pop(ctx context.Context, head bool) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var v V
+	err := kv.Update(ctx, l.store, func(s kv.Store[string, []byte]) error {
+		m, err := l.getMeta(ctx, s)
+		if err != nil {
+			return err
+		}
+		if m.len() == 0 {
+			return ErrEmpty
+		}
+
+		var pos int64
+		if head {
+			pos = m.head
+			m.head++
+		} else {
+			m.tail--
+			pos = m.tail
+		}
+
+		v, err = l.get(ctx, s, pos)
+		if err != nil {
+			return err
+		}
+
+		err = s.Delete(ctx, l.elementKey(pos))
+		if err != nil {
+			return err
+		}
+		if m.len() == 0 {
+			return s.Delete(ctx, l.meta)
+		}
+		return s.Set(ctx, l.meta, m.marshal())
+	})
+	return v, err
+}
+
+func (l *List[V]) get(ctx context.Context, s kv.Store[string, []byte], pos int64) (V, error) {
+	var v V
+	data, err := s.Get(ctx, l.elementKey(pos))
+	if err != nil {
+		return v, err
+	}
+	err = l.codec.Unmarshal(data, &v)
+	return v, err
+}
+
+func (l *List[V]) getMeta(ctx context.Context, s kv.Store[string, []byte]) (listMeta, error) {
+	data, err := s.Get(ctx, l.meta)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return listMeta{}, nil
+	}
+	if err != nil {
+		return listMeta{}, err
+	}
+	return unmarshalListMeta(data)
+}
+
+// elementKey encodes the position preserving its order, so elements of an ordered store are iterated from head to tail.
+func (l *List[V]) elementKey(pos int64) string {
+	return l.elements + string(binary.BigEndian.AppendUint64(nil, uint64(pos)^(1<<63)))
+}
+
+// listMeta holds positions of the list elements in the half-open range [head, tail).
+type listMeta struct {
+	head, tail int64
+}
+
+func (m listMeta) len() int {
+	return int(m.tail - m.head)
+}
+
+func (m listMeta) marshal() []byte {
+	data := binary.BigEndian.AppendUint64(nil, uint64(m.head))
+	return binary.BigEndian.AppendUint64(data, uint64(m.tail))
+}
+
+func unmarshalListMeta(data []byte) (listMeta, error) {
+	if len(data) != 16 {
+		return listMeta{}, fmt.Errorf("%w: %d bytes", errInvalidListMeta, len(data))
+	}
+	return listMeta{
+		head: int64(binary.BigEndian.Uint64(data[:8])),
+		tail: int64(binary.BigEndian.Uint64(data[8:])),
+	}, nil
+}
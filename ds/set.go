@@ -0,0 +1,59 @@
+package ds
+
+import (
+	"context"
+
+	"github.com/royalcat/kv"
+)
+
+// Set is a set of string members stored under a common prefix.
+type Set struct {
+	members kv.Store[string, []byte]
+}
+
+// NewSet creates a set with the given name in the store.
+func NewSet(store kv.Store[string, []byte], name string) *Set {
+	return &Set{
+		members: kv.PrefixBytes(store, namespace("set", name)),
+	}
+}
+
+// setMarker is a value stored for every member, it is not empty as some stores can't distinguish empty values.
+var setMarker = []byte{1}
+
+// SAdd adds the members to the set.
+func (s *Set) SAdd(ctx context.Context, members ...string) error {
+	for _, m := range members {
+		err := s.members.Set(ctx, m, setMarker)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SRem removes the members from the set, removing a missing member is not an error.
+func (s *Set) SRem(ctx context.Context, members ...string) error {
+	for _, m := range members {
+		err := s.members.Delete(ctx, m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SIsMember reports whether the member is in the set.
+func (s *Set) SIsMember(ctx context.Context, member string) (bool, error) {
+	return kv.Has(ctx, s.members, member)
+}
+
+// SCard returns the number of members in the set.
+func (s *Set) SCard(ctx context.Context) (int, error) {
+	return kv.Count(ctx, s.members, "")
+}
+
+// SMembers iterates over the members of the set.
+func (s *Set) SMembers(ctx context.Context, iter kv.KeyIter[string]) error {
+	return kv.RangeKeys(ctx, s.members, iter)
+}
@@ -0,0 +1,280 @@
+// Package election implements leader election on top of lease locks.
+package election
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+var (
+	ErrNoLeader  = errors.New("no leader elected")
+	ErrNotLeader = errors.New("not a leader")
+)
+
+// Options configures an election.
+type Options struct {
+	// TTL is the lease duration, a crashed leader is replaced after it.
+	// The leader ID is written with the same TTL and refreshed while the lease is held,
+	// so a store implementing kv.StoreTTL forgets a crashed leader.
+	TTL time.Duration
+	// PollInterval is the interval of leader checks in Observe.
+	PollInterval time.Duration
+}
+
+// DefaultOptions are options suitable for most elections.
+var DefaultOptions = Options{
+	TTL:          10 * time.Second,
+	PollInterval: time.Second,
+}
+
+// Election is a participant of an election, the leader holds the lease lock of the election
+// and publishes its ID in the store, so other participants can observe it.
+type Election struct {
+	locks kv.LeaseLocks[string]
+	store kv.Store[string, string]
+	name  string
+	id    string
+	opts  Options
+
+	mu   sync.Mutex
+	term *term
+}
+
+// term is a leadership of the participant, its leader ID is refreshed in the background until the term ends.
+type term struct {
+	lease kv.Lease
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// New creates a participant with the given ID in the election with the given name.
+func New(locks kv.LeaseLocks[string], store kv.Store[string, string], name, id string, opts Options) *Election {
+	return &Election{
+		locks: locks,
+		store: store,
+		name:  name,
+		id:    id,
+		opts:  opts,
+	}
+}
+
+// ID returns the ID of the participant.
+func (e *Election) ID() string {
+	return e.id
+}
+
+// Campaign blocks until the participant is elected or the context is done.
+// It returns a channel closed when the leadership is lost or resigned.
+func (e *Election) Campaign(ctx context.Context) (<-chan struct{}, error) {
+	if lease := e.currentLease(); lease != nil {
+		return lease.Lost(), nil
+	}
+
+	// the lease of a lost leadership is released, so its state is cleaned up
+	if stale := e.takeTerm(); stale != nil {
+		err := stale.end(ctx)
+		if err != nil && !errors.Is(err, kv.ErrLeaseLost) {
+			return nil, err
+		}
+	}
+
+	lease, err := e.locks.Lock(ctx, e.lockKey(), e.opts.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = kv.SetWithTTL(ctx, e.store, e.name, e.id, e.opts.TTL)
+	if err != nil {
+		return nil, errors.Join(err, lease.Release(context.WithoutCancel(ctx)))
+	}
+
+	t := &term{
+		lease: lease,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go e.refresh(t)
+
+	e.mu.Lock()
+	e.term = t
+	e.mu.Unlock()
+
+	return lease.Lost(), nil
+}
+
+// IsLeader reports whether the participant currently holds the leadership.
+func (e *Election) IsLeader() bool {
+	return e.currentLease() != nil
+}
+
+// currentLease returns the lease of the leadership if it is not lost.
+func (e *Election) currentLease() kv.Lease {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.term == nil {
+		return nil
+	}
+	select {
+	case <-e.term.lease.Lost():
+		return nil
+	default:
+		return e.term.lease
+	}
+}
+
+// takeTerm removes the current term, lost or not, from the participant.
+func (e *Election) takeTerm() *term {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t := e.term
+	e.term = nil
+	return t
+}
+
+// refresh rewrites the leader ID every third of TTL until the term ends or its lease is lost.
+// A failed write is retried on the next tick, before the ID expires.
+func (e *Election) refresh(t *term) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(max(e.opts.TTL/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-t.lease.Lost():
+			return
+		case <-ticker.C:
+		}
+
+		select {
+		case <-t.lease.Lost():
+			return
+		default:
+		}
+		_ = kv.SetWithTTL(context.Background(), e.store, e.name, e.id, e.opts.TTL)
+	}
+}
+
+// stopRefresh stops refreshing the leader ID and waits for the last refresh.
+func (t *term) stopRefresh() {
+	close(t.stop)
+	<-t.done
+}
+
+// end stops refreshing the leader ID and releases the lease.
+func (t *term) end(ctx context.Context) error {
+	t.stopRefresh()
+	return t.lease.Release(ctx)
+}
+
+// Resign gives up the leadership, so another participant can be elected.
+// It returns ErrNotLeader if the participant is not the leader.
+func (e *Election) Resign(ctx context.Context) error {
+	t := e.takeTerm()
+	if t == nil {
+		return ErrNotLeader
+	}
+
+	t.stopRefresh()
+
+	// the leader ID is removed while the lock is still held, so it can't remove the ID of the next leader
+	var errs []error
+	select {
+	case <-t.lease.Lost():
+	default:
+		errs = append(errs, e.store.Delete(ctx, e.name))
+	}
+	errs = append(errs, t.lease.Release(ctx))
+	return errors.Join(errs...)
+}
+
+// Leader returns the ID of the current leader or ErrNoLeader.
+// The ID of a crashed leader is returned until it expires after TTL or a new leader is elected,
+// a store without kv.StoreTTL keeps it until a new leader is elected.
+func (e *Election) Leader(ctx context.Context) (string, error) {
+	id, err := e.store.Get(ctx, e.name)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return "", ErrNoLeader
+	}
+	return id, err
+}
+
+// Observe polls the leader and sends its ID on every change, an empty ID means there is no leader.
+// The channel is closed when the context is done.
+func (e *Election) Observe(ctx context.Context) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(e.opts.PollInterval)
+		defer ticker.Stop()
+
+		last := ""
+		first := true
+		for {
+			id, err := e.Leader(ctx)
+			if err == nil || errors.Is(err, ErrNoLeader) {
+				if first || id != last {
+					select {
+					case ch <- id:
+					case <-ctx.Done():
+						return
+					}
+					first = false
+					last = id
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch
+}
+
+// Run campaigns until the context is done, calling lead every time the participant is elected.
+// The context passed to lead is cancelled when the leadership is lost, after lead returns the participant resigns.
+func (e *Election) Run(ctx context.Context, lead func(ctx context.Context)) error {
+	for {
+		lost, err := e.Campaign(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		leadCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-lost:
+				cancel()
+			case <-leadCtx.Done():
+			}
+		}()
+		lead(leadCtx)
+		cancel()
+
+		err = e.Resign(context.WithoutCancel(ctx))
+		if err != nil && !errors.Is(err, kv.ErrLeaseLost) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (e *Election) lockKey() string {
+	return e.name + "/lock"
+}
@@ -0,0 +1,42 @@
+package kv
+
+import "context"
+
+// KeyIter is a function type that represents an iterator function for keys.
+type KeyIter[K any] func(k K) error
+
+// StoreKeys is an optional interface for stores able to iterate keys without reading and decoding values.
+type StoreKeys[K any] interface {
+	// RangeKeys iterates over all keys in the store and calls the provided iterator function for each key.
+	// The iterator function should return non-nil error to stop the iteration.
+	RangeKeys(ctx context.Context, iter KeyIter[K]) error
+
+	// RangeKeysWithPrefix iterates over all keys in the store that have the given prefix
+	// and calls the provided iterator function for each key.
+	// The iterator function should return non-nil error to stop the iteration.
+	RangeKeysWithPrefix(ctx context.Context, prefix K, iter KeyIter[K]) error
+}
+
+// RangeKeys iterates over all keys in the store.
+// It uses [StoreKeys] when the store implements it and falls back to [Store.Range] otherwise.
+func RangeKeys[K, V any](ctx context.Context, s Store[K, V], iter KeyIter[K]) error {
+	if sk, ok := s.(StoreKeys[K]); ok {
+		return sk.RangeKeys(ctx, iter)
+	}
+
+	return s.Range(ctx, func(k K, _ V) error {
+		return iter(k)
+	})
+}
+
+// RangeKeysWithPrefix iterates over all keys in the store that have the given prefix.
+// It uses [StoreKeys] when the store implements it and falls back to [Store.RangeWithPrefix] otherwise.
+func RangeKeysWithPrefix[K, V any](ctx context.Context, s Store[K, V], prefix K, iter KeyIter[K]) error {
+	if sk, ok := s.(StoreKeys[K]); ok {
+		return sk.RangeKeysWithPrefix(ctx, prefix, iter)
+	}
+
+	return s.RangeWithPrefix(ctx, prefix, func(k K, _ V) error {
+		return iter(k)
+	})
+}
@@ -107,10 +107,67 @@ func (s *StoreBinaryKey[K, V, KP]) RangeWithOptions(ctx context.Context, opt bad
 	})
 }
 
+// RangeKeys implements kv.StoreKeys.
+func (s *StoreBinaryKey[K, V, KP]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithOptions(ctx, badger.DefaultIteratorOptions, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *StoreBinaryKey[K, V, KP]) RangeKeysWithPrefix(ctx context.Context, k K, iter kv.KeyIter[K]) error {
+	p, err := k.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return s.RangeKeysWithOptions(ctx, prefixOptions(p), iter)
+}
+
+func (s *StoreBinaryKey[K, V, KP]) RangeKeysWithOptions(ctx context.Context, opt badger.IteratorOptions, iter kv.KeyIter[K]) error {
+	return s.DB.View(func(txn *badger.Txn) error {
+		return txRangeKeys(ctx, txn, opt, func(kb []byte) error {
+			key, err := unmarshalKey[K, KP](kb)
+			if err != nil {
+				return err
+			}
+
+			return iter(key)
+		})
+	})
+}
+
+// Has implements kv.StoreHas.
+func (s *StoreBinaryKey[K, V, KP]) Has(ctx context.Context, k K) (ok bool, err error) {
+	kb, err := k.MarshalBinary()
+	if err != nil {
+		return false, err
+	}
+
+	err = s.DB.View(func(txn *badger.Txn) error {
+		ok, err = txHas(txn, kb)
+		return err
+	})
+	return ok, err
+}
+
+// Count implements kv.StoreCount.
+func (s *StoreBinaryKey[K, V, KP]) Count(ctx context.Context, prefix K) (n int, err error) {
+	p, err := prefix.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.DB.View(func(txn *badger.Txn) error {
+		n, err = txCount(ctx, txn, prefixOptions(p))
+		return err
+	})
+	return n, err
+}
+
 func (s *StoreBinaryKey[K, V, KP]) Transaction(update bool) (kv.Store[K, V], error) {
 	tx := s.DB.NewTransaction(update)
 	return &transactionBinaryKey[K, V, KP]{
-		txn: tx,
+		txn:         tx,
+		badgerStore: s.badgerStore,
 	}, nil
 }
 
@@ -120,7 +177,7 @@ type transactionBinaryKey[K encoding.BinaryMarshaler, V any, KP binaryPointer[K]
 }
 
 func (t *transactionBinaryKey[K, V, KP]) Close(ctx context.Context) error {
-	return t.txn.Commit()
+	return txCommit(t.txn)
 }
 
 // Delete implements kv.Store.
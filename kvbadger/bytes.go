@@ -2,6 +2,7 @@ package kvbadger
 
 import (
 	"context"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/royalcat/kv"
@@ -46,6 +47,15 @@ func (s *StoreRaw[K, V]) Set(ctx context.Context, k K, v V) error {
 	})
 }
 
+var _ kv.StoreTTL[string, string] = (*StoreRaw[string, string])(nil)
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *StoreRaw[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	return s.DB.Update(func(txn *badger.Txn) error {
+		return txSetTTL[V](txn, []byte(k), v, s.Options, ttl)
+	})
+}
+
 func (s *StoreRaw[K, V]) Get(ctx context.Context, k K) (v V, err error) {
 	err = s.DB.View(func(txn *badger.Txn) error {
 		v, err = txGet[V](txn, []byte(k), s.Options)
@@ -64,6 +74,17 @@ func (s *StoreRaw[K, V]) RangeWithPrefix(ctx context.Context, k K, iter kv.Iter[
 	return s.RangeWithOptions(ctx, prefixOptions([]byte(k)), iter)
 }
 
+var _ kv.StoreOrdered[string, string] = (*StoreRaw[string, string])(nil)
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *StoreRaw[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return s.DB.View(func(txn *badger.Txn) error {
+		return txRangeOrdered[V](ctx, txn, bytesOrder(order), s.Options, func(k []byte, v V) error {
+			return iter(K(k), v)
+		})
+	})
+}
+
 func (s *StoreRaw[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
 	return s.RangeWithOptions(ctx, badger.DefaultIteratorOptions, iter)
 }
@@ -76,12 +97,54 @@ func (s *StoreRaw[K, V]) RangeWithOptions(ctx context.Context, opt badger.Iterat
 	})
 }
 
+var _ kv.StoreKeys[string] = (*StoreRaw[string, string])(nil)
+
+// RangeKeys implements kv.StoreKeys.
+func (s *StoreRaw[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithOptions(ctx, badger.DefaultIteratorOptions, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *StoreRaw[K, V]) RangeKeysWithPrefix(ctx context.Context, k K, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithOptions(ctx, prefixOptions([]byte(k)), iter)
+}
+
+func (s *StoreRaw[K, V]) RangeKeysWithOptions(ctx context.Context, opt badger.IteratorOptions, iter kv.KeyIter[K]) error {
+	return s.DB.View(func(txn *badger.Txn) error {
+		return txRangeKeys(ctx, txn, opt, func(k []byte) error {
+			return iter(K(k))
+		})
+	})
+}
+
+var _ kv.StoreHas[string] = (*StoreRaw[string, string])(nil)
+var _ kv.StoreCount[string] = (*StoreRaw[string, string])(nil)
+
+// Has implements kv.StoreHas.
+func (s *StoreRaw[K, V]) Has(ctx context.Context, k K) (ok bool, err error) {
+	err = s.DB.View(func(txn *badger.Txn) error {
+		ok, err = txHas(txn, []byte(k))
+		return err
+	})
+	return ok, err
+}
+
+// Count implements kv.StoreCount.
+func (s *StoreRaw[K, V]) Count(ctx context.Context, prefix K) (n int, err error) {
+	err = s.DB.View(func(txn *badger.Txn) error {
+		n, err = txCount(ctx, txn, prefixOptions([]byte(prefix)))
+		return err
+	})
+	return n, err
+}
+
 var _ kv.TransactionalStore[string, string] = (*StoreRaw[string, string])(nil)
 
 func (s *StoreRaw[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
 	tx := s.DB.NewTransaction(update)
 	return &transactionBytes[K, V]{
-		tx: tx,
+		tx:  tx,
+		opt: s.Options,
 	}, nil
 }
 
@@ -91,9 +154,10 @@ type transactionBytes[K, V kv.Bytes] struct {
 }
 
 var _ kv.Store[string, string] = (*transactionBytes[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*transactionBytes[string, string])(nil)
 
 func (t *transactionBytes[K, V]) Close(ctx context.Context) error {
-	return t.tx.Commit()
+	return txCommit(t.tx)
 }
 
 // Delete implements kv.Store.
@@ -130,6 +194,11 @@ func (t *transactionBytes[K, V]) Set(ctx context.Context, k K, v V) error {
 
 }
 
+// SetWithTTL implements kv.StoreTTL.
+func (t *transactionBytes[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	return txSetTTL[V](t.tx, []byte(k), v, t.opt, ttl)
+}
+
 // Range implements kv.Store.
 func (t *transactionBytes[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
 	return txRange[V](ctx, t.tx, badger.DefaultIteratorOptions, t.opt, func(k []byte, v V) error {
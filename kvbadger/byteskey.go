@@ -2,6 +2,7 @@ package kvbadger
 
 import (
 	"context"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/royalcat/kv"
@@ -29,6 +30,15 @@ func (s *StoreBytesKey[K, V]) Set(ctx context.Context, k K, v V) error {
 	})
 }
 
+var _ kv.StoreTTL[string, string] = (*StoreBytesKey[string, string])(nil)
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *StoreBytesKey[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	return s.DB.Update(func(txn *badger.Txn) error {
+		return txSetTTL(txn, []byte(k), v, s.Options, ttl)
+	})
+}
+
 func (s *StoreBytesKey[K, V]) Get(ctx context.Context, k K) (v V, err error) {
 	err = s.DB.View(func(txn *badger.Txn) error {
 		v, err = txGet[V](txn, []byte(k), s.Options)
@@ -64,17 +74,15 @@ func (s *StoreBytesKey[K, V]) RangeWithPrefix(ctx context.Context, k K, iter kv.
 	return s.RangeWithOptions(ctx, prefixOptions([]byte(k)), iter)
 }
 
-func (s *StoreBytesKey[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
-	opts := badger.DefaultIteratorOptions
-	opts.Reverse = order.Reverse
+var _ kv.StoreOrdered[string, string] = (*StoreBytesKey[string, string])(nil)
 
-	s.DB.View(func(txn *badger.Txn) error {
-		return txRange(ctx, txn, opts, s.Options, func(k []byte, v V) error {
+// RangeOrdered implements kv.StoreOrdered.
+func (s *StoreBytesKey[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return s.DB.View(func(txn *badger.Txn) error {
+		return txRangeOrdered(ctx, txn, bytesOrder(order), s.Options, func(k []byte, v V) error {
 			return iter(K(k), v)
 		})
 	})
-
-	return nil
 }
 
 func (s *StoreBytesKey[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
@@ -89,6 +97,47 @@ func (s *StoreBytesKey[K, V]) RangeWithOptions(ctx context.Context, opt badger.I
 	})
 }
 
+var _ kv.StoreKeys[string] = (*StoreBytesKey[string, string])(nil)
+
+// RangeKeys implements kv.StoreKeys.
+func (s *StoreBytesKey[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithOptions(ctx, badger.DefaultIteratorOptions, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *StoreBytesKey[K, V]) RangeKeysWithPrefix(ctx context.Context, k K, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithOptions(ctx, prefixOptions([]byte(k)), iter)
+}
+
+func (s *StoreBytesKey[K, V]) RangeKeysWithOptions(ctx context.Context, opt badger.IteratorOptions, iter kv.KeyIter[K]) error {
+	return s.DB.View(func(txn *badger.Txn) error {
+		return txRangeKeys(ctx, txn, opt, func(k []byte) error {
+			return iter(K(k))
+		})
+	})
+}
+
+var _ kv.StoreHas[string] = (*StoreBytesKey[string, string])(nil)
+var _ kv.StoreCount[string] = (*StoreBytesKey[string, string])(nil)
+
+// Has implements kv.StoreHas.
+func (s *StoreBytesKey[K, V]) Has(ctx context.Context, k K) (ok bool, err error) {
+	err = s.DB.View(func(txn *badger.Txn) error {
+		ok, err = txHas(txn, []byte(k))
+		return err
+	})
+	return ok, err
+}
+
+// Count implements kv.StoreCount.
+func (s *StoreBytesKey[K, V]) Count(ctx context.Context, prefix K) (n int, err error) {
+	err = s.DB.View(func(txn *badger.Txn) error {
+		n, err = txCount(ctx, txn, prefixOptions([]byte(prefix)))
+		return err
+	})
+	return n, err
+}
+
 var _ kv.TransactionalStore[string, string] = (*StoreBytesKey[string, string])(nil)
 
 // Transaction implements kv.TransactionalStore.
@@ -106,9 +155,10 @@ type transactionBytesKey[K kv.Bytes, V any] struct {
 }
 
 var _ kv.Store[string, string] = (*transactionBytesKey[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*transactionBytesKey[string, string])(nil)
 
 func (t *transactionBytesKey[K, V]) Close(ctx context.Context) error {
-	return t.tx.Commit()
+	return txCommit(t.tx)
 }
 
 // Delete implements kv.Store.
@@ -143,6 +193,11 @@ func (t *transactionBytesKey[K, V]) Set(ctx context.Context, k K, v V) error {
 	return txSet(t.tx, []byte(k), v, t.Options)
 }
 
+// SetWithTTL implements kv.StoreTTL.
+func (t *transactionBytesKey[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	return txSetTTL(t.tx, []byte(k), v, t.Options, ttl)
+}
+
 // Range implements kv.Store.
 func (t *transactionBytesKey[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
 	return txRange(ctx, t.tx, badger.DefaultIteratorOptions, t.Options, func(k []byte, v V) error {
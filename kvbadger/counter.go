@@ -0,0 +1,103 @@
+package kvbadger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+)
+
+// NewCounter creates a kv.Counter stored in the given badger database using kv.CounterValue encoding.
+// Increments run in update transactions and are retried on transaction conflicts.
+// When ttl is positive, it is applied when a counter is created, further increments keep the original expiration.
+func NewCounter[K kv.Bytes](db *badger.DB, ttl time.Duration) kv.Counter[K] {
+	return &counter[K]{
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+type counter[K kv.Bytes] struct {
+	db  *badger.DB
+	ttl time.Duration
+}
+
+var _ kv.Counter[string] = (*counter[string])(nil)
+
+// Incr implements kv.Counter.
+func (c *counter[K]) Incr(ctx context.Context, k K, delta int64) (int64, error) {
+	for {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		var out kv.CounterValue
+		err := c.db.Update(func(txn *badger.Txn) error {
+			v, err := txGetCounter(txn, []byte(k))
+			if err != nil {
+				return err
+			}
+
+			out = v.Incr(delta, c.ttl, time.Now())
+			data, err := out.MarshalBinary()
+			if err != nil {
+				return err
+			}
+
+			entry := badger.NewEntry([]byte(k), data)
+			if !out.ExpiresAt.IsZero() {
+				// badger expiration has seconds precision, so it is rounded up
+				// and the precise expiration is checked from the value
+				entry.ExpiresAt = uint64(out.ExpiresAt.Unix()) + 1
+			}
+			return txn.SetEntry(entry)
+		})
+		if errors.Is(err, badger.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return out.Value, nil
+	}
+}
+
+// Decr implements kv.Counter.
+func (c *counter[K]) Decr(ctx context.Context, k K, delta int64) (int64, error) {
+	return c.Incr(ctx, k, -delta)
+}
+
+// GetCounter implements kv.Counter.
+func (c *counter[K]) GetCounter(ctx context.Context, k K) (int64, error) {
+	var v kv.CounterValue
+	err := c.db.View(func(txn *badger.Txn) (err error) {
+		v, err = txGetCounter(txn, []byte(k))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if v.Expired(time.Now()) {
+		return 0, nil
+	}
+
+	return v.Value, nil
+}
+
+func txGetCounter(txn *badger.Txn, k []byte) (kv.CounterValue, error) {
+	var v kv.CounterValue
+
+	item, err := txn.Get(k)
+	if err == badger.ErrKeyNotFound {
+		return v, nil
+	}
+	if err != nil {
+		return v, err
+	}
+
+	err = item.Value(v.UnmarshalBinary)
+	return v, err
+}
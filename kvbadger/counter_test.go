@@ -0,0 +1,21 @@
+package kvbadger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestCounter(t *testing.T) {
+	testsuite.GoldenCounter(t, func(ttl time.Duration) (kv.Counter[string], error) {
+		db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+		if err != nil {
+			return nil, err
+		}
+		return kvbadger.NewCounter[string](db, ttl), nil
+	})
+}
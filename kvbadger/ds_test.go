@@ -0,0 +1,17 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestDataStructures(t *testing.T) {
+	testsuite.GoldenDataStructures(t, func() (kv.Store[string, []byte], error) {
+		opts := kvbadger.DefaultOptions[[]byte]("")
+		opts.BadgerOptions.InMemory = true
+		return kvbadger.NewRaw[string, []byte](opts)
+	})
+}
@@ -0,0 +1,23 @@
+package kvbadger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestStoreLeaseLocks(t *testing.T) {
+	testsuite.GoldenLeaseLocks(t, func() (kv.LeaseLocks[string], error) {
+		opts := kvbadger.DefaultOptions[kv.LeaseRecord]("")
+		opts.BadgerOptions.InMemory = true
+		opts.Codec = kv.CodecBinary[kv.LeaseRecord, *kv.LeaseRecord]{}
+		store, err := kvbadger.New[string, kv.LeaseRecord](opts)
+		if err != nil {
+			return nil, err
+		}
+		return kv.NewStoreLeaseLocks(store, 10*time.Millisecond), nil
+	})
+}
@@ -0,0 +1,179 @@
+package kvbadger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+)
+
+const lockPollInterval = 10 * time.Millisecond
+
+// NewLocks creates kv.Locks stored as kv.LockRecord values in the given badger database,
+// so they are shared by all goroutines using the database. Badger locks its directory for a single process,
+// so the locks coordinate users of one opened database and never other processes.
+//
+// The record of a key is stored under prefix+key, so locks may guard keys holding data in the same database.
+// The prefix must not be a prefix of data keys, records are visible to stores ranging over the whole database.
+//
+// A lock expires after ttl and then can be reclaimed by another owner, so ttl must exceed the longest time a lock is held.
+// Every acquisition gets its own owner ID, so an expired lock can't be released by its previous holder.
+func NewLocks[K kv.Bytes](db *badger.DB, prefix []byte, ttl time.Duration) *Locks[K] {
+	return &Locks[K]{
+		db:     db,
+		prefix: bytes.Clone(prefix),
+		ttl:    ttl,
+		id:     kv.NewLockOwner(),
+		held:   map[string]string{},
+	}
+}
+
+type Locks[K kv.Bytes] struct {
+	db     *badger.DB
+	prefix []byte
+	ttl    time.Duration
+
+	id  string
+	seq atomic.Uint64
+
+	mu   sync.Mutex
+	held map[string]string
+}
+
+var _ kv.Locks[string] = (*Locks[string])(nil)
+
+// Lock implements kv.Locks.
+func (l *Locks[K]) Lock(ctx context.Context, key K) error {
+	for {
+		ok, err := l.TryLock(ctx, key)
+		if err != nil || ok {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// TryLock implements kv.Locks.
+func (l *Locks[K]) TryLock(ctx context.Context, key K) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	owner := fmt.Sprintf("%s-%d", l.id, l.seq.Add(1))
+	acquired := false
+	err := l.db.Update(func(txn *badger.Txn) error {
+		r, err := txGetLock(txn, l.recordKey(string(key)))
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if r.Held(now) {
+			return nil
+		}
+
+		r = kv.LockRecord{Owner: owner, ExpiresAt: now.Add(l.ttl)}
+		data, err := r.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		// badger expiration has seconds precision, so it is rounded up
+		// and the precise expiration is checked from the value
+		entry := badger.NewEntry(l.recordKey(string(key)), data)
+		entry.ExpiresAt = uint64(r.ExpiresAt.Unix()) + 1
+		acquired = true
+		return txn.SetEntry(entry)
+	})
+	if errors.Is(err, badger.ErrConflict) {
+		// another owner changed the lock concurrently
+		return false, nil
+	}
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.held[string(key)] = owner
+	l.mu.Unlock()
+	return true, nil
+}
+
+// Unlock implements kv.Locks.
+// It returns kv.ErrLockNotHeld if the lock expired and was reclaimed by another owner.
+func (l *Locks[K]) Unlock(ctx context.Context, key K) error {
+	l.mu.Lock()
+	owner, ok := l.held[string(key)]
+	delete(l.held, string(key))
+	l.mu.Unlock()
+	if !ok {
+		return kv.ErrLockNotHeld
+	}
+
+	return l.release(l.recordKey(string(key)), owner)
+}
+
+// Close implements kv.Locks.
+func (l *Locks[K]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	held := l.held
+	l.held = map[string]string{}
+	l.mu.Unlock()
+
+	var errs []error
+	for k, owner := range held {
+		err := l.release(l.recordKey(k), owner)
+		if err != nil && !errors.Is(err, kv.ErrLockNotHeld) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Locks[K]) release(k []byte, owner string) error {
+	for {
+		err := l.db.Update(func(txn *badger.Txn) error {
+			r, err := txGetLock(txn, k)
+			if err != nil {
+				return err
+			}
+			if r.Owner != owner || !r.Held(time.Now()) {
+				return kv.ErrLockNotHeld
+			}
+			return txn.Delete(k)
+		})
+		if errors.Is(err, badger.ErrConflict) {
+			continue
+		}
+		return err
+	}
+}
+
+// recordKey returns the key of the lock record of key.
+func (l *Locks[K]) recordKey(key string) []byte {
+	return append(bytes.Clone(l.prefix), key...)
+}
+
+func txGetLock(txn *badger.Txn, k []byte) (kv.LockRecord, error) {
+	var r kv.LockRecord
+
+	item, err := txn.Get(k)
+	if err == badger.ErrKeyNotFound {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+
+	err = item.Value(r.UnmarshalBinary)
+	return r, err
+}
@@ -0,0 +1,43 @@
+package kvbadger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestLocks(t *testing.T) {
+	testsuite.GoldenLocks(t, func() (kv.Locks[string], error) {
+		db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+		if err != nil {
+			return nil, err
+		}
+		return kvbadger.NewLocks[string](db, []byte("lock:"), time.Minute), nil
+	})
+}
+
+func TestStaleLocks(t *testing.T) {
+	testsuite.GoldenStaleLocks(t, func(ttl time.Duration) (kv.Locks[string], kv.Locks[string], error) {
+		db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+		if err != nil {
+			return nil, nil, err
+		}
+		return kvbadger.NewLocks[string](db, []byte("lock:"), ttl), kvbadger.NewLocks[string](db, []byte("lock:"), ttl), nil
+	})
+}
+
+func TestLocksWithData(t *testing.T) {
+	testsuite.GoldenLocksWithData(t, func() (kv.Store[string, string], kv.Locks[string], error) {
+		opts := kvbadger.DefaultOptions[string]("")
+		opts.BadgerOptions.InMemory = true
+		store, err := kvbadger.New[string, string](opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, kvbadger.NewLocks[string](store.DB, []byte("lock:"), time.Minute), nil
+	})
+}
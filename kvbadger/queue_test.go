@@ -0,0 +1,17 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		opts := kvbadger.DefaultOptions[[]byte]("")
+		opts.BadgerOptions.InMemory = true
+		return kvbadger.NewRaw[string, []byte](opts)
+	})
+}
@@ -0,0 +1,37 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/ratelimit"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestTokenBucket(t *testing.T) {
+	testsuite.GoldenTokenBucket(t, func(opts ratelimit.TokenBucketOptions) (ratelimit.Limiter[string], error) {
+		storeOpts := kvbadger.DefaultOptions[ratelimit.TokenBucketState]("")
+		storeOpts.BadgerOptions.InMemory = true
+		storeOpts.Codec = kv.CodecBinary[ratelimit.TokenBucketState, *ratelimit.TokenBucketState]{}
+		store, err := kvbadger.New[string, ratelimit.TokenBucketState](storeOpts)
+		if err != nil {
+			return nil, err
+		}
+		return ratelimit.NewTokenBucket(store, opts), nil
+	})
+}
+
+func TestRateLimitExpiry(t *testing.T) {
+	testsuite.GoldenRateLimitExpiry(t, func() (kv.Store[string, ratelimit.TokenBucketState], error) {
+		opts := kvbadger.DefaultOptions[ratelimit.TokenBucketState]("")
+		opts.BadgerOptions.InMemory = true
+		opts.Codec = kv.CodecBinary[ratelimit.TokenBucketState, *ratelimit.TokenBucketState]{}
+		return kvbadger.New[string, ratelimit.TokenBucketState](opts)
+	}, func() (kv.Store[string, ratelimit.SlidingWindowState], error) {
+		opts := kvbadger.DefaultOptions[ratelimit.SlidingWindowState]("")
+		opts.BadgerOptions.InMemory = true
+		opts.Codec = kv.CodecBinary[ratelimit.SlidingWindowState, *ratelimit.SlidingWindowState]{}
+		return kvbadger.New[string, ratelimit.SlidingWindowState](opts)
+	})
+}
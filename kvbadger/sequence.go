@@ -0,0 +1,48 @@
+package kvbadger
+
+import (
+	"context"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+)
+
+// NewSequence creates a kv.Sequence backed by badger's native sequence, leasing bandwidth IDs at once.
+func NewSequence[K kv.Bytes](db *badger.DB, key K, bandwidth uint64) (kv.Sequence, error) {
+	seq, err := db.GetSequence([]byte(key), max(bandwidth, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sequence{seq: seq}, nil
+}
+
+type sequence struct {
+	seq *badger.Sequence
+}
+
+var _ kv.Sequence = (*sequence)(nil)
+
+// Next implements kv.Sequence.
+func (s *sequence) Next(ctx context.Context) (uint64, error) {
+	id, err := s.seq.Next()
+	if err != nil {
+		return 0, err
+	}
+
+	// badger sequence starts from zero, but kv.Sequence IDs are positive
+	if id == 0 {
+		return s.seq.Next()
+	}
+	return id, nil
+}
+
+// Release implements kv.Sequence.
+func (s *sequence) Release(ctx context.Context) error {
+	err := s.seq.Release()
+	if err == badger.ErrKeyNotFound {
+		// nothing was leased yet
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestSequence(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testsuite.GoldenSequence(t, func(key string) (kv.Sequence, error) {
+		return kvbadger.NewSequence(db, key, 10)
+	})
+}
@@ -0,0 +1,17 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/timeseries"
+)
+
+func TestTimeSeries(t *testing.T) {
+	testsuite.GoldenTimeSeries(t, func() (timeseries.Store, error) {
+		opts := kvbadger.DefaultOptions[[]byte]("")
+		opts.BadgerOptions.InMemory = true
+		return kvbadger.NewRaw[string, []byte](opts)
+	})
+}
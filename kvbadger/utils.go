@@ -1,8 +1,11 @@
 package kvbadger
 
 import (
+	"bytes"
 	"context"
 	"encoding"
+	"errors"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/royalcat/kv"
@@ -20,6 +23,22 @@ func unmarshalKey[K encoding.BinaryMarshaler, KP binaryPointer[K]](data []byte)
 	return k, err
 }
 
+func bytesOrder[K kv.Bytes](order kv.Order[K]) kv.Order[[]byte] {
+	return kv.Order[[]byte]{
+		Min:     []byte(order.Min),
+		Max:     []byte(order.Max),
+		Reverse: order.Reverse,
+	}
+}
+
+func txCommit(txn *badger.Txn) error {
+	err := txn.Commit()
+	if err == badger.ErrConflict {
+		return errors.Join(kv.ErrConflict, err)
+	}
+	return err
+}
+
 func txGet[V any](txn *badger.Txn, k []byte, opts Options[V]) (V, error) {
 	var v V
 
@@ -42,15 +61,40 @@ func txGet[V any](txn *badger.Txn, k []byte, opts Options[V]) (V, error) {
 	return v, err
 }
 
+func txHas(txn *badger.Txn, k []byte) (bool, error) {
+	_, err := txn.Get(k)
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func txCount(ctx context.Context, txn *badger.Txn, opt badger.IteratorOptions) (int, error) {
+	n := 0
+	err := txRangeKeys(ctx, txn, opt, func([]byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
 func txSet[V any](txn *badger.Txn, k []byte, v V, opts Options[V]) error {
+	return txSetTTL(txn, k, v, opts, opts.DefaultTTL)
+}
+
+func txSetTTL[V any](txn *badger.Txn, k []byte, v V, opts Options[V], ttl time.Duration) error {
 	data, err := opts.Codec.Marshal(v)
 	if err != nil {
 		return err
 	}
 
 	entry := badger.NewEntry([]byte(k), data)
-	if opts.DefaultTTL > 0 {
-		entry = entry.WithTTL(opts.DefaultTTL)
+	if ttl > 0 {
+		// badger expires keys at whole seconds, round up so a key never expires early
+		entry.ExpiresAt = uint64(time.Now().Add(ttl + time.Second - 1).Unix())
 	}
 
 	return txn.SetEntry(entry)
@@ -82,3 +126,73 @@ func txRange[V any](ctx context.Context, txn *badger.Txn, opt badger.IteratorOpt
 
 	return nil
 }
+
+func txRangeKeys(ctx context.Context, txn *badger.Txn, opt badger.IteratorOptions, iter kv.KeyIter[[]byte]) error {
+	opt.PrefetchValues = false
+
+	it := txn.NewIterator(opt)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := iter(it.Item().KeyCopy(nil))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func txRangeOrdered[V any](ctx context.Context, txn *badger.Txn, order kv.Order[[]byte], opts Options[V], iter kv.Iter[[]byte, V]) error {
+	opt := badger.DefaultIteratorOptions
+	opt.Reverse = order.Reverse
+
+	it := txn.NewIterator(opt)
+	defer it.Close()
+
+	switch {
+	case !order.Reverse && len(order.Min) > 0:
+		it.Seek(order.Min)
+	case order.Reverse && len(order.Max) > 0:
+		// reverse seek finds the largest key less or equal to max, max itself is excluded
+		it.Seek(order.Max)
+		if it.Valid() && bytes.Equal(it.Item().Key(), order.Max) {
+			it.Next()
+		}
+	default:
+		it.Rewind()
+	}
+
+	for ; it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		item := it.Item()
+		key := item.Key()
+		if order.Reverse && len(order.Min) > 0 && bytes.Compare(key, order.Min) < 0 {
+			break
+		}
+		if !order.Reverse && len(order.Max) > 0 && bytes.Compare(key, order.Max) >= 0 {
+			break
+		}
+
+		var v V
+		err := item.Value(func(val []byte) error {
+			return opts.Codec.Unmarshal(val, &v)
+		})
+		if err != nil {
+			return err
+		}
+		err = iter(item.KeyCopy(nil), v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
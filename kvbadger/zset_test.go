@@ -0,0 +1,17 @@
+package kvbadger_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvbadger"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+)
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		opts := kvbadger.DefaultOptions[[]byte]("")
+		opts.BadgerOptions.InMemory = true
+		return kvbadger.NewRaw[string, []byte](opts)
+	})
+}
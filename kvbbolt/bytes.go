@@ -96,7 +96,7 @@ func (s *bytesStore[K, V]) Get(ctx context.Context, k K) (V, error) {
 			return kv.ErrKeyNotFound
 		}
 
-		v = V(val)
+		v = V(bytes.Clone(val))
 
 		return nil
 	})
@@ -112,13 +112,106 @@ func (s *bytesStore[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error
 		}
 
 		return b.ForEach(func(k, v []byte) error {
-			return iter(K(k), V(v))
+			return iter(K(bytes.Clone(k)), V(bytes.Clone(v)))
 		})
 	})
 }
 
 // RangeWithPrefix implements kv.Store.
 func (s *bytesStore[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b == nil {
+			return nil
+		}
+
+		return rangePrefix(ctx, b, []byte(prefix), iter)
+	})
+}
+
+// rangePrefix and rangeOrdered pass copies to the iterator,
+// as bbolt keys and values point into its memory map and are valid only during the transaction.
+func rangePrefix[K, V kv.Bytes](ctx context.Context, b *bbolt.Bucket, prefix []byte, iter kv.Iter[K, V]) error {
+	cur := b.Cursor()
+	k, v := cur.Seek(prefix)
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		if err := iter(K(bytes.Clone(k)), V(bytes.Clone(v))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ kv.StoreOrdered[string, string] = (*bytesStore[string, string])(nil)
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *bytesStore[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b == nil {
+			return nil
+		}
+
+		return rangeOrdered(ctx, b, []byte(order.Min), []byte(order.Max), order.Reverse, iter)
+	})
+}
+
+func rangeOrdered[K, V kv.Bytes](ctx context.Context, b *bbolt.Bucket, min, max []byte, reverse bool, iter kv.Iter[K, V]) error {
+	cur := b.Cursor()
+
+	if reverse {
+		var k, v []byte
+		if len(max) > 0 {
+			// seek finds the first key greater or equal to max, max itself is excluded
+			k, v = cur.Seek(max)
+			if k == nil {
+				k, v = cur.Last()
+			} else {
+				k, v = cur.Prev()
+			}
+		} else {
+			k, v = cur.Last()
+		}
+
+		for ; k != nil && (len(min) == 0 || bytes.Compare(k, min) >= 0); k, v = cur.Prev() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := iter(K(bytes.Clone(k)), V(bytes.Clone(v))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var k, v []byte
+	if len(min) > 0 {
+		k, v = cur.Seek(min)
+	} else {
+		k, v = cur.First()
+	}
+
+	for ; k != nil && (len(max) == 0 || bytes.Compare(k, max) < 0); k, v = cur.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := iter(K(bytes.Clone(k)), V(bytes.Clone(v))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ kv.StoreKeys[string] = (*bytesStore[string, string])(nil)
+
+// RangeKeys implements kv.StoreKeys.
+func (s *bytesStore[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithPrefix(ctx, K(""), iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *bytesStore[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
 	return s.db.View(func(tx *bbolt.Tx) error {
 		b := tx.Bucket(s.bucket)
 		if b == nil {
@@ -126,9 +219,9 @@ func (s *bytesStore[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter k
 		}
 
 		cur := b.Cursor()
-		k, v := cur.Seek([]byte(prefix))
-		for ; k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = cur.Next() {
-			if err := iter(K(k), V(v)); err != nil {
+		k, _ := cur.Seek([]byte(prefix))
+		for ; k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = cur.Next() {
+			if err := iter(K(bytes.Clone(k))); err != nil {
 				return err
 			}
 		}
@@ -136,3 +229,45 @@ func (s *bytesStore[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter k
 		return nil
 	})
 }
+
+var _ kv.StoreHas[string] = (*bytesStore[string, string])(nil)
+var _ kv.StoreCount[string] = (*bytesStore[string, string])(nil)
+
+// Has implements kv.StoreHas.
+func (s *bytesStore[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	var ok bool
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b == nil {
+			return nil
+		}
+
+		ok = b.Get([]byte(k)) != nil
+		return nil
+	})
+	return ok, err
+}
+
+// Count implements kv.StoreCount.
+func (s *bytesStore[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	if len(prefix) == 0 {
+		var n int
+		err := s.db.View(func(tx *bbolt.Tx) error {
+			b := tx.Bucket(s.bucket)
+			if b == nil {
+				return nil
+			}
+
+			n = b.Stats().KeyN
+			return nil
+		})
+		return n, err
+	}
+
+	n := 0
+	err := s.RangeKeysWithPrefix(ctx, prefix, func(K) error {
+		n++
+		return nil
+	})
+	return n, err
+}
@@ -25,3 +25,20 @@ func TestGolden(t *testing.T) {
 	t.Parallel()
 	testsuite.GoldenStrings(t, newKV(t.TempDir))
 }
+
+func TestPageBytes(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenPageBytes(t, func() (kv.Store[[]byte, []byte], error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return kvbbolt.NewBytes[[]byte, []byte](db, []byte("test")), nil
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenUpdate(t, newKV(t.TempDir))
+}
@@ -0,0 +1,90 @@
+package kvbbolt
+
+import (
+	"context"
+	"time"
+
+	"github.com/royalcat/kv"
+	"go.etcd.io/bbolt"
+)
+
+// NewCounter creates a kv.Counter stored in the given bbolt bucket using kv.CounterValue encoding.
+// When ttl is positive, it is applied when a counter is created, further increments keep the original expiration.
+// bbolt has no native expiration, so expired counters are reset on the next increment.
+func NewCounter[K kv.Bytes](db *bbolt.DB, bucket []byte, ttl time.Duration) kv.Counter[K] {
+	return &counter[K]{
+		db:     db,
+		bucket: bucket,
+		ttl:    ttl,
+	}
+}
+
+type counter[K kv.Bytes] struct {
+	db     *bbolt.DB
+	bucket []byte
+	ttl    time.Duration
+}
+
+var _ kv.Counter[string] = (*counter[string])(nil)
+
+// Incr implements kv.Counter.
+func (c *counter[K]) Incr(ctx context.Context, k K, delta int64) (int64, error) {
+	var out kv.CounterValue
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(c.bucket)
+		if err != nil {
+			return err
+		}
+
+		var v kv.CounterValue
+		if data := b.Get([]byte(k)); data != nil {
+			if err := v.UnmarshalBinary(data); err != nil {
+				return err
+			}
+		}
+
+		out = v.Incr(delta, c.ttl, time.Now())
+		data, err := out.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(k), data)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return out.Value, nil
+}
+
+// Decr implements kv.Counter.
+func (c *counter[K]) Decr(ctx context.Context, k K, delta int64) (int64, error) {
+	return c.Incr(ctx, k, -delta)
+}
+
+// GetCounter implements kv.Counter.
+func (c *counter[K]) GetCounter(ctx context.Context, k K) (int64, error) {
+	var v kv.CounterValue
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(c.bucket)
+		if b == nil {
+			return nil
+		}
+
+		data := b.Get([]byte(k))
+		if data == nil {
+			return nil
+		}
+
+		return v.UnmarshalBinary(data)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if v.Expired(time.Now()) {
+		return 0, nil
+	}
+
+	return v.Value, nil
+}
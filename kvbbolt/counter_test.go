@@ -0,0 +1,23 @@
+package kvbbolt_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbbolt"
+	"github.com/royalcat/kv/testsuite"
+	"go.etcd.io/bbolt"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenCounter(t, func(ttl time.Duration) (kv.Counter[string], error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, err
+		}
+		return kvbbolt.NewCounter[string](db, []byte("counters"), ttl), nil
+	})
+}
@@ -0,0 +1,171 @@
+package kvbbolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/royalcat/kv"
+	"go.etcd.io/bbolt"
+)
+
+const lockPollInterval = 10 * time.Millisecond
+
+// NewLocks creates kv.Locks stored as kv.LockRecord values in the given bbolt bucket,
+// so they are shared by all goroutines using the database. bbolt locks its file for a single process,
+// so the locks coordinate users of one opened database and never other processes.
+//
+// The record of a key is stored under prefix+key, so locks may guard keys holding data in the same bucket.
+// The prefix must not be a prefix of data keys, records are visible to stores ranging over the whole bucket.
+//
+// A lock expires after ttl and then can be reclaimed by another owner, so ttl must exceed the longest time a lock is held.
+// Every acquisition gets its own owner ID, so an expired lock can't be released by its previous holder.
+func NewLocks[K kv.Bytes](db *bbolt.DB, bucket, prefix []byte, ttl time.Duration) *Locks[K] {
+	return &Locks[K]{
+		db:     db,
+		bucket: bucket,
+		prefix: bytes.Clone(prefix),
+		ttl:    ttl,
+		id:     kv.NewLockOwner(),
+		held:   map[string]string{},
+	}
+}
+
+type Locks[K kv.Bytes] struct {
+	db     *bbolt.DB
+	bucket []byte
+	prefix []byte
+	ttl    time.Duration
+
+	id  string
+	seq atomic.Uint64
+
+	mu   sync.Mutex
+	held map[string]string
+}
+
+var _ kv.Locks[string] = (*Locks[string])(nil)
+
+// Lock implements kv.Locks.
+func (l *Locks[K]) Lock(ctx context.Context, key K) error {
+	for {
+		ok, err := l.TryLock(ctx, key)
+		if err != nil || ok {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// TryLock implements kv.Locks.
+func (l *Locks[K]) TryLock(ctx context.Context, key K) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	owner := fmt.Sprintf("%s-%d", l.id, l.seq.Add(1))
+	acquired := false
+	err := l.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(l.bucket)
+		if err != nil {
+			return err
+		}
+
+		r, err := getLock(b, l.recordKey(string(key)))
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if r.Held(now) {
+			return nil
+		}
+
+		data, err := kv.LockRecord{Owner: owner, ExpiresAt: now.Add(l.ttl)}.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		acquired = true
+		return b.Put(l.recordKey(string(key)), data)
+	})
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.held[string(key)] = owner
+	l.mu.Unlock()
+	return true, nil
+}
+
+// Unlock implements kv.Locks.
+// It returns kv.ErrLockNotHeld if the lock expired and was reclaimed by another owner.
+func (l *Locks[K]) Unlock(ctx context.Context, key K) error {
+	l.mu.Lock()
+	owner, ok := l.held[string(key)]
+	delete(l.held, string(key))
+	l.mu.Unlock()
+	if !ok {
+		return kv.ErrLockNotHeld
+	}
+
+	return l.release(l.recordKey(string(key)), owner)
+}
+
+// Close implements kv.Locks.
+func (l *Locks[K]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	held := l.held
+	l.held = map[string]string{}
+	l.mu.Unlock()
+
+	var errs []error
+	for k, owner := range held {
+		err := l.release(l.recordKey(k), owner)
+		if err != nil && !errors.Is(err, kv.ErrLockNotHeld) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Locks[K]) release(k []byte, owner string) error {
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(l.bucket)
+		if b == nil {
+			return kv.ErrLockNotHeld
+		}
+
+		r, err := getLock(b, k)
+		if err != nil {
+			return err
+		}
+		if r.Owner != owner || !r.Held(time.Now()) {
+			return kv.ErrLockNotHeld
+		}
+		return b.Delete(k)
+	})
+}
+
+// recordKey returns the key of the lock record of key.
+func (l *Locks[K]) recordKey(key string) []byte {
+	return append(bytes.Clone(l.prefix), key...)
+}
+
+func getLock(b *bbolt.Bucket, k []byte) (kv.LockRecord, error) {
+	var r kv.LockRecord
+	data := b.Get(k)
+	if data == nil {
+		return r, nil
+	}
+	err := r.UnmarshalBinary(data)
+	return r, err
+}
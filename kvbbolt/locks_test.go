@@ -0,0 +1,45 @@
+package kvbbolt_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbbolt"
+	"github.com/royalcat/kv/testsuite"
+	"go.etcd.io/bbolt"
+)
+
+func TestLocks(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenLocks(t, func() (kv.Locks[string], error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, err
+		}
+		return kvbbolt.NewLocks[string](db, []byte("locks"), []byte("lock:"), time.Minute), nil
+	})
+}
+
+func TestStaleLocks(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenStaleLocks(t, func(ttl time.Duration) (kv.Locks[string], kv.Locks[string], error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return kvbbolt.NewLocks[string](db, []byte("locks"), []byte("lock:"), ttl), kvbbolt.NewLocks[string](db, []byte("locks"), []byte("lock:"), ttl), nil
+	})
+}
+
+func TestLocksWithData(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenLocksWithData(t, func() (kv.Store[string, string], kv.Locks[string], error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return kvbbolt.NewBytes[string, string](db, []byte("test")), kvbbolt.NewLocks[string](db, []byte("test"), []byte("lock:"), time.Minute), nil
+	})
+}
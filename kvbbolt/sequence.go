@@ -0,0 +1,86 @@
+package kvbbolt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/royalcat/kv"
+	"go.etcd.io/bbolt"
+)
+
+// NewSequence creates a kv.Sequence backed by the native sequence of the bucket,
+// leasing bandwidth IDs at once with a single update of the bucket sequence.
+func NewSequence(db *bbolt.DB, bucket []byte, bandwidth uint64) kv.Sequence {
+	return &sequence{
+		db:        db,
+		bucket:    bucket,
+		bandwidth: max(bandwidth, 1),
+		next:      1,
+	}
+}
+
+type sequence struct {
+	mu        sync.Mutex
+	db        *bbolt.DB
+	bucket    []byte
+	bandwidth uint64
+
+	// next is the next ID to return, leased is the last leased ID
+	next, leased uint64
+}
+
+var _ kv.Sequence = (*sequence)(nil)
+
+// Next implements kv.Sequence.
+func (s *sequence) Next(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next > s.leased {
+		var leased uint64
+		err := s.db.Update(func(tx *bbolt.Tx) error {
+			b, err := tx.CreateBucketIfNotExists(s.bucket)
+			if err != nil {
+				return err
+			}
+
+			leased = b.Sequence() + s.bandwidth
+			return b.SetSequence(leased)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		s.next = leased - s.bandwidth + 1
+		s.leased = leased
+	}
+
+	id := s.next
+	s.next++
+	return id, nil
+}
+
+// Release implements kv.Sequence.
+func (s *sequence) Release(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next > s.leased {
+		return nil
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b == nil || b.Sequence() != s.leased {
+			return nil
+		}
+
+		return b.SetSequence(s.next - 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.leased = s.next - 1
+	return nil
+}
@@ -0,0 +1,24 @@
+package kvbbolt_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbbolt"
+	"github.com/royalcat/kv/testsuite"
+	"go.etcd.io/bbolt"
+)
+
+func TestSequence(t *testing.T) {
+	t.Parallel()
+	db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testsuite.GoldenSequence(t, func(key string) (kv.Sequence, error) {
+		return kvbbolt.NewSequence(db, []byte(key), 10), nil
+	})
+}
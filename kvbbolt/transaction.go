@@ -0,0 +1,132 @@
+package kvbbolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/royalcat/kv"
+	"go.etcd.io/bbolt"
+)
+
+var _ kv.TransactionalStore[string, string] = (*bytesStore[string, string])(nil)
+
+// Transaction implements kv.TransactionalStore.
+// bbolt allows only one update transaction at a time, so an update transaction blocks other writers until it is closed.
+func (s *bytesStore[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	tx, err := s.db.Begin(update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bytesTransaction[K, V]{
+		tx:     tx,
+		bucket: s.bucket,
+	}, nil
+}
+
+type bytesTransaction[K, V kv.Bytes] struct {
+	tx     *bbolt.Tx
+	bucket []byte
+}
+
+var _ kv.Store[string, string] = (*bytesTransaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*bytesTransaction[string, string])(nil)
+
+// Close implements kv.Store.
+func (t *bytesTransaction[K, V]) Close(ctx context.Context) error {
+	if !t.tx.Writable() {
+		return t.tx.Rollback()
+	}
+	err := t.tx.Commit()
+	if errors.Is(err, bbolt.ErrTxClosed) {
+		return nil
+	}
+	return err
+}
+
+func (t *bytesTransaction[K, V]) writeBucket() (*bbolt.Bucket, error) {
+	return t.tx.CreateBucketIfNotExists(t.bucket)
+}
+
+// Delete implements kv.Store.
+func (t *bytesTransaction[K, V]) Delete(ctx context.Context, k K) error {
+	b, err := t.writeBucket()
+	if err != nil {
+		return err
+	}
+
+	return b.Delete([]byte(k))
+}
+
+// Edit implements kv.Store.
+func (t *bytesTransaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	b := t.tx.Bucket(t.bucket)
+	if b == nil {
+		return kv.ErrKeyNotFound
+	}
+
+	val := b.Get([]byte(k))
+	if val == nil {
+		return kv.ErrKeyNotFound
+	}
+
+	newVal, err := edit(ctx, V(bytes.Clone(val)))
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(k), []byte(newVal))
+}
+
+// Get implements kv.Store.
+func (t *bytesTransaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	var v V
+
+	b := t.tx.Bucket(t.bucket)
+	if b == nil {
+		return v, kv.ErrKeyNotFound
+	}
+
+	val := b.Get([]byte(k))
+	if val == nil {
+		return v, kv.ErrKeyNotFound
+	}
+
+	return V(bytes.Clone(val)), nil
+}
+
+// Set implements kv.Store.
+func (t *bytesTransaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	b, err := t.writeBucket()
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(k), []byte(v))
+}
+
+// Range implements kv.Store.
+func (t *bytesTransaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return t.RangeWithPrefix(ctx, K(""), iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *bytesTransaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	b := t.tx.Bucket(t.bucket)
+	if b == nil {
+		return nil
+	}
+
+	return rangePrefix(ctx, b, []byte(prefix), iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *bytesTransaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	b := t.tx.Bucket(t.bucket)
+	if b == nil {
+		return nil
+	}
+
+	return rangeOrdered(ctx, b, []byte(order.Min), []byte(order.Max), order.Reverse, iter)
+}
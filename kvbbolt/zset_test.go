@@ -0,0 +1,23 @@
+package kvbbolt_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/royalcat/kv/kvbbolt"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+	"go.etcd.io/bbolt"
+)
+
+func TestSortedSet(t *testing.T) {
+	t.Parallel()
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		db, err := bbolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return kvbbolt.NewBytes[string, []byte](db, []byte("test")), nil
+	})
+}
@@ -1,6 +1,7 @@
 package kvbitcask
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -54,6 +55,9 @@ func (s *BitcaskStore[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) err
 	data, err := tx.Get(bitcask.Key(k))
 	if err != nil {
 		tx.Discard()
+		if err == bitcask.ErrKeyNotFound {
+			return kv.ErrKeyNotFound
+		}
 		return err
 	}
 	v, err := edit(ctx, V(data))
@@ -107,8 +111,46 @@ func (s *BitcaskStore[K, V]) RangeWithPrefix(ctx context.Context, k K, iter kv.I
 			return err
 		}
 
-		return iter(K(k), V(v))
+		return iter(K(bytes.Clone(k)), V(bytes.Clone(v)))
+	})
+}
+
+var _ kv.StoreKeys[string] = (*BitcaskStore[string, string])(nil)
+
+// RangeKeys implements kv.StoreKeys.
+func (s *BitcaskStore[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.DB.ForEach(func(k bitcask.Key) error {
+		return iter(K(bytes.Clone(k)))
+	})
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *BitcaskStore[K, V]) RangeKeysWithPrefix(ctx context.Context, k K, iter kv.KeyIter[K]) error {
+	return s.DB.Scan(bitcask.Key(k), func(k bitcask.Key) error {
+		return iter(K(bytes.Clone(k)))
+	})
+}
+
+var _ kv.StoreHas[string] = (*BitcaskStore[string, string])(nil)
+var _ kv.StoreCount[string] = (*BitcaskStore[string, string])(nil)
+
+// Has implements kv.StoreHas.
+func (s *BitcaskStore[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	return s.DB.Has(bitcask.Key(k)), nil
+}
+
+// Count implements kv.StoreCount.
+func (s *BitcaskStore[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	if len(prefix) == 0 {
+		return s.DB.Len(), nil
+	}
+
+	n := 0
+	err := s.DB.Scan(bitcask.Key(prefix), func(bitcask.Key) error {
+		n++
+		return nil
 	})
+	return n, err
 }
 
 func (s *BitcaskStore[K, V]) Close(ctx context.Context) error {
@@ -1,12 +1,14 @@
 package kvbitcask_test
 
 import (
+	"context"
 	"path"
 	"testing"
 
 	"github.com/royalcat/kv"
 	"github.com/royalcat/kv/kvbitcask"
 	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGolden(t *testing.T) {
@@ -14,3 +16,40 @@ func TestGolden(t *testing.T) {
 		return kvbitcask.New[string, string](path.Join(t.TempDir(), "bitcask"))
 	})
 }
+
+func TestRangeKeysCopy(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, err := kvbitcask.New[[]byte, []byte](path.Join(t.TempDir(), "bitcask"))
+	require.NoError(err)
+	defer s.Close(ctx)
+
+	require.NoError(s.Set(ctx, []byte("key"), []byte("value")))
+
+	keys := [][]byte{}
+	require.NoError(s.RangeKeysWithPrefix(ctx, []byte("k"), func(k []byte) error {
+		keys = append(keys, k)
+		return nil
+	}))
+	require.NoError(s.RangeKeys(ctx, func(k []byte) error {
+		keys = append(keys, k)
+		return nil
+	}))
+	require.NoError(s.RangeWithPrefix(ctx, []byte("k"), func(k, v []byte) error {
+		keys = append(keys, k, v)
+		return nil
+	}))
+
+	// iterated slices belong to the caller, modifying them doesn't change the store
+	for _, k := range keys {
+		k[0] = 'x'
+	}
+
+	v, err := s.Get(ctx, []byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), v)
+	ok, err := s.Has(ctx, []byte("key"))
+	require.NoError(err)
+	require.True(ok)
+}
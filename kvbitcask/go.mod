@@ -7,6 +7,7 @@ toolchain go1.22.5
 require (
 	github.com/royalcat/kv v0.0.0-20240707205211-fedd4883af85
 	github.com/royalcat/kv/testsuite v0.0.0-20240723124828-253d2ecf5312
+	github.com/stretchr/testify v1.9.0
 	go.mills.io/bitcask/v2 v2.0.3
 )
 
@@ -21,7 +22,6 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
-	github.com/stretchr/testify v1.9.0 // indirect
 	golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
 	golang.org/x/sys v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -0,0 +1,23 @@
+module github.com/royalcat/kv/kvbuntdb
+
+go 1.22.5
+
+require (
+	github.com/royalcat/kv v0.0.0-20240723125224-456de4e86ee6
+	github.com/royalcat/kv/testsuite v0.0.0-20240723125224-456de4e86ee6
+	github.com/stretchr/testify v1.9.0
+	github.com/tidwall/buntdb v1.3.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	github.com/tidwall/btree v1.4.2 // indirect
+	github.com/tidwall/gjson v1.14.3 // indirect
+	github.com/tidwall/grect v0.1.4 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	github.com/tidwall/rtred v0.1.2 // indirect
+	github.com/tidwall/tinyqueue v0.1.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,22 @@
+package kvbuntdb
+
+import (
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+type Options[V any] struct {
+	Codec kv.Codec[V]
+	// Path is the database file path, ":memory:" opens an in-memory database.
+	Path string
+	// DefaultTTL is applied to every written value when positive.
+	DefaultTTL time.Duration
+}
+
+func DefaultOptions[V any](path string) Options[V] {
+	return Options[V]{
+		Codec: kv.CodecJSON[V]{},
+		Path:  path,
+	}
+}
@@ -0,0 +1,149 @@
+package kvbuntdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/tidwall/buntdb"
+)
+
+var errReadOnlyTransaction = errors.New("transaction is read-only")
+
+// New opens a buntdb database at the options path.
+func New[K kv.Bytes, V any](opts Options[V]) (*Store[K, V], error) {
+	db, err := buntdb.Open(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store[K, V]{
+		DB:      db,
+		Options: opts,
+	}, nil
+}
+
+// Store is a kv.Store on top of a buntdb database, keys are ordered by the buntdb keys tree.
+//
+// Expired values are invisible for reads and removed by buntdb in the background.
+type Store[K kv.Bytes, V any] struct {
+	DB      *buntdb.DB
+	Options Options[V]
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+var _ kv.StoreCount[string] = (*Store[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*Store[string, string])(nil)
+var _ kv.TransactionalStore[string, string] = (*Store[string, string])(nil)
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	return s.DB.Close()
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	return s.DB.Update(func(tx *buntdb.Tx) error {
+		return set(tx, string(k), v, s.Options.Codec, s.Options.DefaultTTL)
+	})
+}
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *Store[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	return s.DB.Update(func(tx *buntdb.Tx) error {
+		return set(tx, string(k), v, s.Options.Codec, ttl)
+	})
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (v V, err error) {
+	err = s.DB.View(func(tx *buntdb.Tx) error {
+		v, err = get(tx, string(k), s.Options.Codec)
+		return err
+	})
+	return v, err
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	return s.DB.Update(func(tx *buntdb.Tx) error {
+		return del(tx, string(k))
+	})
+}
+
+// Edit implements kv.Store.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	return s.DB.Update(func(tx *buntdb.Tx) error {
+		v, err := get(tx, string(k), s.Options.Codec)
+		if err != nil {
+			return err
+		}
+		v, err = edit(ctx, v)
+		if err != nil {
+			return err
+		}
+		return set(tx, string(k), v, s.Options.Codec, s.Options.DefaultTTL)
+	})
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB.View, "", s.Options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB.View, string(prefix), s.Options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *Store[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, s.DB.View, order, s.Options.Codec, iter)
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB.View, "", iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB.View, string(prefix), iter)
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	err := s.DB.View(func(tx *buntdb.Tx) error {
+		_, err := tx.Get(string(k))
+		return err
+	})
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Count implements kv.StoreCount.
+func (s *Store[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	return count(ctx, s.DB.View, string(prefix))
+}
+
+// Transaction implements kv.TransactionalStore.
+//
+// A buntdb transaction holds the database lock until Close, an update transaction blocks all other transactions
+// and a read-only one blocks writers, so the store itself must not be written while a transaction is open in the same goroutine.
+func (s *Store[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	tx, err := s.DB.Begin(update)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction[K, V]{
+		tx:      tx,
+		update:  update,
+		options: s.Options,
+	}, nil
+}
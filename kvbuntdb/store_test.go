@@ -0,0 +1,121 @@
+package kvbuntdb_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvbuntdb"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemory[V any]() (kv.Store[string, V], error) {
+	return kvbuntdb.New[string, V](kvbuntdb.DefaultOptions[V](":memory:"))
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newMemory)
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	testsuite.FuzzPrefixBytes(t, newMemory)
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newMemory)
+}
+
+func TestPersistent(t *testing.T) {
+	testsuite.GoldenStrings(t, func() (kv.Store[string, string], error) {
+		opts := kvbuntdb.DefaultOptions[string](filepath.Join(t.TempDir(), "kv.db"))
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvbuntdb.New[string, string](opts)
+	})
+}
+
+func newMemoryRaw() (*kvbuntdb.Store[string, []byte], error) {
+	opts := kvbuntdb.DefaultOptions[[]byte](":memory:")
+	opts.Codec = kv.CodecBytes[[]byte]{}
+	return kvbuntdb.New[string, []byte](opts)
+}
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestRangeOrderedPages(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	s, err := newMemoryRaw()
+	require.NoError(err)
+	defer s.Close(ctx)
+
+	// more keys than a single scan page
+	keys := []string{}
+	for i := range 1000 {
+		k := fmt.Sprintf("key%04d", i)
+		require.NoError(s.Set(ctx, k, []byte(k)))
+		keys = append(keys, k)
+	}
+
+	collect := func(order kv.Order[string]) []string {
+		got := []string{}
+		err := s.RangeOrdered(ctx, order, func(k string, _ []byte) error {
+			got = append(got, k)
+			return nil
+		})
+		require.NoError(err)
+		return got
+	}
+
+	require.Equal(keys, collect(kv.Order[string]{}))
+	require.Equal(keys[100:900], collect(kv.Order[string]{Min: "key0100", Max: "key0900"}))
+
+	reversed := slices.Clone(keys[100:900])
+	slices.Reverse(reversed)
+	require.Equal(reversed, collect(kv.Order[string]{Min: "key0100", Max: "key0900", Reverse: true}))
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	opts := kvbuntdb.DefaultOptions[string](":memory:")
+	opts.DefaultTTL = 50 * time.Millisecond
+	s, err := kvbuntdb.New[string, string](opts)
+	require.NoError(err)
+	defer s.Close(ctx)
+
+	require.NoError(s.Set(ctx, "a", "1"))
+	ok, err := s.Has(ctx, "a")
+	require.NoError(err)
+	require.True(ok)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = s.Get(ctx, "a")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+	n, err := s.Count(ctx, "")
+	require.NoError(err)
+	require.Zero(n)
+}
+
+func TestRateLimitExpiry(t *testing.T) {
+	testsuite.GoldenRateLimitExpiry(t, newMemory, newMemory)
+}
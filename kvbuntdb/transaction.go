@@ -0,0 +1,98 @@
+package kvbuntdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/tidwall/buntdb"
+)
+
+type transaction[K kv.Bytes, V any] struct {
+	tx      *buntdb.Tx
+	update  bool
+	options Options[V]
+	closed  bool
+}
+
+var _ kv.Store[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*transaction[string, string])(nil)
+
+// Close implements kv.Store, it commits an update transaction.
+func (t *transaction[K, V]) Close(ctx context.Context) error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if !t.update {
+		return t.tx.Rollback()
+	}
+	return t.tx.Commit()
+}
+
+// Set implements kv.Store.
+func (t *transaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return set(t.tx, string(k), v, t.options.Codec, t.options.DefaultTTL)
+}
+
+// SetWithTTL implements kv.StoreTTL.
+func (t *transaction[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return set(t.tx, string(k), v, t.options.Codec, ttl)
+}
+
+// Get implements kv.Store.
+func (t *transaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(t.tx, string(k), t.options.Codec)
+}
+
+// Delete implements kv.Store.
+func (t *transaction[K, V]) Delete(ctx context.Context, k K) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return del(t.tx, string(k))
+}
+
+// Edit implements kv.Store.
+func (t *transaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+
+	v, err := get(t.tx, string(k), t.options.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return set(t.tx, string(k), v, t.options.Codec, t.options.DefaultTTL)
+}
+
+// Range implements kv.Store.
+func (t *transaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.view, "", t.options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *transaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.view, string(prefix), t.options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *transaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, t.view, order, t.options.Codec, iter)
+}
+
+func (t *transaction[K, V]) view(fn func(tx *buntdb.Tx) error) error {
+	return fn(t.tx)
+}
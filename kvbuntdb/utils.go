@@ -0,0 +1,171 @@
+package kvbuntdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/tidwall/buntdb"
+)
+
+// pageSize is the number of items read by a single scan while ranging,
+// items are read in pages so the iterator is free to write to the store.
+const pageSize = 256
+
+// viewFunc runs fn in a read transaction.
+type viewFunc func(fn func(tx *buntdb.Tx) error) error
+
+func get[V any](tx *buntdb.Tx, k string, codec kv.Codec[V]) (V, error) {
+	var v V
+	data, err := tx.Get(k)
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return v, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	err = codec.Unmarshal([]byte(data), &v)
+	return v, err
+}
+
+func set[V any](tx *buntdb.Tx, k string, v V, codec kv.Codec[V], ttl time.Duration) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var opts *buntdb.SetOptions
+	if ttl > 0 {
+		opts = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+	_, _, err = tx.Set(k, string(data), opts)
+	return err
+}
+
+func del(tx *buntdb.Tx, k string) error {
+	_, err := tx.Delete(k)
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// bound is an optional range bound.
+type bound struct {
+	key string
+	set bool
+}
+
+// rangeBounds iterates over keys in [lower, upper) with the ascend and descend scans of the keys tree.
+func rangeBounds(ctx context.Context, view viewFunc, lower, upper bound, reverse bool, iter func(k, v string) error) error {
+	type item struct{ k, v string }
+
+	// last is the last visited key, the next page starts after it
+	var last bound
+	for {
+		page := make([]item, 0, pageSize)
+		collect := func(k, v string) bool {
+			if last.set && k == last.key {
+				return true
+			}
+			if reverse && lower.set && k < lower.key {
+				return false
+			}
+			if !reverse && upper.set && k >= upper.key {
+				return false
+			}
+			if reverse && upper.set && k >= upper.key {
+				// the descend scan includes its pivot
+				return true
+			}
+			page = append(page, item{k, v})
+			return len(page) < pageSize
+		}
+
+		err := view(func(tx *buntdb.Tx) error {
+			switch {
+			case !reverse && last.set:
+				return tx.AscendGreaterOrEqual("", last.key, collect)
+			case !reverse && lower.set:
+				return tx.AscendGreaterOrEqual("", lower.key, collect)
+			case !reverse:
+				return tx.Ascend("", collect)
+			case last.set:
+				return tx.DescendLessOrEqual("", last.key, collect)
+			case upper.set:
+				return tx.DescendLessOrEqual("", upper.key, collect)
+			default:
+				return tx.Descend("", collect)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, it := range page {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+			if err := iter(it.k, it.v); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+		last = bound{key: page[len(page)-1].k, set: true}
+	}
+}
+
+func rangeValues[K kv.Bytes, V any](ctx context.Context, view viewFunc, lower, upper bound, reverse bool, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeBounds(ctx, view, lower, upper, reverse, func(k, data string) error {
+		var v V
+		if err := codec.Unmarshal([]byte(data), &v); err != nil {
+			return err
+		}
+		return iter(K(k), v)
+	})
+}
+
+func rangePrefix[K kv.Bytes, V any](ctx context.Context, view viewFunc, prefix string, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, view, optional(prefix), prefixEnd(prefix), false, codec, iter)
+}
+
+func rangeOrdered[K kv.Bytes, V any](ctx context.Context, view viewFunc, order kv.Order[K], codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, view, optional(string(order.Min)), optional(string(order.Max)), order.Reverse, codec, iter)
+}
+
+func rangeKeys[K kv.Bytes](ctx context.Context, view viewFunc, prefix string, iter kv.KeyIter[K]) error {
+	return rangeBounds(ctx, view, optional(prefix), prefixEnd(prefix), false, func(k, _ string) error {
+		return iter(K(k))
+	})
+}
+
+func count(ctx context.Context, view viewFunc, prefix string) (int, error) {
+	n := 0
+	err := rangeBounds(ctx, view, optional(prefix), prefixEnd(prefix), false, func(_, _ string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// prefixEnd returns the smallest key greater than all keys with the prefix, unset if there is no such key.
+func prefixEnd(prefix string) bound {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return bound{key: string(end[:i+1]), set: true}
+		}
+	}
+	return bound{}
+}
+
+// optional returns an unset bound for an empty key.
+func optional(k string) bound {
+	return bound{key: k, set: k != ""}
+}
@@ -0,0 +1,26 @@
+package kvfs
+
+import (
+	"io/fs"
+
+	"github.com/royalcat/kv"
+)
+
+type Options[V any] struct {
+	Codec kv.Codec[V]
+	// Dir is the root directory of the store, it is created if not exists.
+	Dir string
+	// FileMode is the permission of value files.
+	FileMode fs.FileMode
+	// DirMode is the permission of created directories.
+	DirMode fs.FileMode
+}
+
+func DefaultOptions[V any](dir string) Options[V] {
+	return Options[V]{
+		Codec:    kv.CodecJSON[V]{},
+		Dir:      dir,
+		FileMode: 0o644,
+		DirMode:  0o755,
+	}
+}
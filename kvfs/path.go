@@ -0,0 +1,138 @@
+package kvfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// dirSuffix is appended to directory names, so a key can be a value and a prefix of other keys at the same time,
+// e.g. keys "app" and "app/db" are stored as files "app" and "app.d/db".
+const dirSuffix = ".d"
+
+// chunkSuffix is appended to continuation directories of names longer than maxName,
+// escaped names never end with a lone "%".
+const chunkSuffix = "%"
+
+const (
+	// maxName is the maximum length of an escaped name, leaving space for the directory suffix within the common 255 bytes limit.
+	maxName = 250
+	// chunkSize is the length of continuation directory names.
+	chunkSize = 200
+)
+
+// keyPath returns the file path of the key relative to the store root.
+// Every "/" separated segment of the key is escaped, all but the last one become directories.
+func keyPath(key string) string {
+	segments := strings.Split(key, "/")
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		parts[i] = segmentPath(seg, i < len(segments)-1)
+	}
+	return filepath.Join(parts...)
+}
+
+// segmentPath returns the path of an escaped key segment, either a value file or a directory.
+// Names longer than maxName are split into continuation directories.
+func segmentPath(seg string, dir bool) string {
+	name := escapeSegment(seg)
+
+	var parts []string
+	for len(name) > maxName {
+		cut := chunkSize
+		// escape sequences are never split and a name never starts with a dot, as temporary files do
+		for name[cut-1] == '%' || name[cut-2] == '%' || name[cut] == '.' {
+			cut--
+		}
+		parts = append(parts, name[:cut]+chunkSuffix)
+		name = name[cut:]
+	}
+
+	if dir {
+		name += dirSuffix
+	}
+	return filepath.Join(append(parts, name)...)
+}
+
+// escapeSegment escapes a key segment into a portable file name.
+//
+// Bytes other than ASCII letters, digits and "-_.~+=,@" are escaped as %XX,
+// so are a leading dot (keeping "." and ".." unreachable and temporary files hidden)
+// and a dot of a trailing ".d" (keeping file names distinct from directory names).
+// An empty segment is stored as a single "%".
+func escapeSegment(seg string) string {
+	if seg == "" {
+		return "%"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+		if isSafe(c) && !(c == '.' && (i == 0 || seg[i:] == dirSuffix)) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// unescapeSegment is the inverse of escapeSegment, it fails for names not produced by escapeSegment.
+func unescapeSegment(name string) (string, error) {
+	if name == "%" {
+		return "", nil
+	}
+
+	seg, err := unescape(name)
+	if err != nil {
+		return "", err
+	}
+	// non-canonical names would alias keys of other files
+	if escapeSegment(seg) != name {
+		return "", fmt.Errorf("invalid escaped name %q", name)
+	}
+	return seg, nil
+}
+
+// unescape decodes %XX escape sequences of a name or a part of it.
+func unescape(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+2 >= len(name) {
+			return "", fmt.Errorf("invalid escaped name %q", name)
+		}
+		h, ok1 := unhex(name[i+1])
+		l, ok2 := unhex(name[i+2])
+		if !ok1 || !ok2 {
+			return "", fmt.Errorf("invalid escaped name %q", name)
+		}
+		b.WriteByte(h<<4 | l)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+func isSafe(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-_.~+=,@", c) >= 0
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
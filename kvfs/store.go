@@ -0,0 +1,302 @@
+package kvfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/royalcat/kv"
+)
+
+var errEmptyDir = errors.New("store directory must not be empty")
+
+// New creates a store keeping every key as a file under the options directory.
+//
+// Keys are split by "/" into directories, see [Store] for the layout.
+func New[K kv.Bytes, V any](opts Options[V]) (*Store[K, V], error) {
+	if opts.Dir == "" {
+		return nil, errEmptyDir
+	}
+	if opts.FileMode == 0 {
+		opts.FileMode = 0o644
+	}
+	if opts.DirMode == 0 {
+		opts.DirMode = 0o755
+	}
+
+	err := os.MkdirAll(opts.Dir, opts.DirMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store[K, V]{
+		Options: opts,
+	}, nil
+}
+
+// Store is a kv.Store keeping values in files of a directory tree.
+//
+// Every "/" separated key segment is escaped into a file name, all segments but the last one are directories
+// with a ".d" suffix, e.g. the key "app/db/url" is stored in the file "app.d/db.d/url".
+// Values are written to a temporary file renamed over the value file, so readers never observe partial writes.
+// Writes through the store are serialized, making Edit isolated from other writes through the same store.
+type Store[K kv.Bytes, V any] struct {
+	Options Options[V]
+
+	// mu serializes writes, as deletes remove emptied directories
+	mu sync.Mutex
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+
+// FS returns a read-only view of the store directory.
+// File names in the view are the escaped keys as they are stored on disk.
+func (s *Store[K, V]) FS() fs.FS {
+	return os.DirFS(s.Options.Dir)
+}
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	return nil
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.set(string(k), v)
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return s.get(s.path(string(k)))
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(string(k))
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// emptied directories are removed to keep the tree clean, removal of a non-empty directory fails and stops it
+	for dir := filepath.Dir(path); dir != filepath.Clean(s.Options.Dir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Edit implements kv.Store.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, err := s.get(s.path(string(k)))
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return s.set(string(k), v)
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return s.RangeWithPrefix(ctx, K(""), iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+//
+// Only directories which may contain keys with the prefix are walked.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return s.walkPrefix(ctx, string(prefix), func(key, path string) error {
+		v, err := s.get(path)
+		if errors.Is(err, kv.ErrKeyNotFound) {
+			// deleted while walking
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return iter(K(key), v)
+	})
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithPrefix(ctx, K(""), iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return s.walkPrefix(ctx, string(prefix), func(key, _ string) error {
+		return iter(K(key))
+	})
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	_, err := os.Stat(s.path(string(k)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store[K, V]) path(key string) string {
+	return filepath.Join(s.Options.Dir, keyPath(key))
+}
+
+func (s *Store[K, V]) get(path string) (V, error) {
+	var v V
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return v, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	err = s.Options.Codec.Unmarshal(data, &v)
+	return v, err
+}
+
+func (s *Store[K, V]) set(key string, v V) error {
+	data, err := s.Options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(key)
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, s.Options.DirMode)
+	if err != nil {
+		return err
+	}
+
+	// the temporary file name starts with a dot, which is always escaped in key file names
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = f.Chmod(s.Options.FileMode)
+	}
+	err = errors.Join(err, f.Close())
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// walkPrefix calls fn with the key and the file path of every key with the prefix.
+func (s *Store[K, V]) walkPrefix(ctx context.Context, prefix string, fn func(key, path string) error) error {
+	dirs := strings.Split(prefix, "/")
+	partial := dirs[len(dirs)-1]
+	dirs = dirs[:len(dirs)-1]
+
+	dir := s.Options.Dir
+	keyPrefix := ""
+	for _, seg := range dirs {
+		dir = filepath.Join(dir, segmentPath(seg, true))
+		keyPrefix += seg + "/"
+	}
+
+	return walkDir(ctx, dir, dir, "", keyPrefix, partial, fn)
+}
+
+// walkDir walks entries of the directory holding keys starting with keyPrefix,
+// only entries with a segment starting with partial are visited.
+//
+// Inside continuation directories of a long name, segDir is the directory the name starts in
+// and pending is the escaped part of the name in continuation directories walked so far.
+func walkDir(ctx context.Context, dir, segDir, pending, keyPrefix, partial string, fn func(key, path string) error) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		if entry.IsDir() && len(name) > 1 && strings.HasSuffix(name, chunkSuffix) {
+			chunk := pending + strings.TrimSuffix(name, chunkSuffix)
+			decoded, err := unescape(chunk)
+			if err != nil || !(strings.HasPrefix(decoded, partial) || strings.HasPrefix(partial, decoded)) {
+				continue
+			}
+			err = walkDir(ctx, path, segDir, chunk, keyPrefix, partial, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if entry.IsDir() {
+			if !strings.HasSuffix(name, dirSuffix) {
+				continue
+			}
+			name = strings.TrimSuffix(name, dirSuffix)
+		} else if !entry.Type().IsRegular() {
+			continue
+		}
+
+		// temporary and foreign files are not keys
+		seg, err := unescapeSegment(pending + name)
+		if err != nil || !strings.HasPrefix(seg, partial) {
+			continue
+		}
+		if pending != "" && filepath.Join(segDir, segmentPath(seg, entry.IsDir())) != path {
+			continue
+		}
+
+		if entry.IsDir() {
+			err = walkDir(ctx, path, path, "", keyPrefix+seg+"/", "", fn)
+		} else {
+			// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+			err = fn(keyPrefix+seg, path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
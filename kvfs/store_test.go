@@ -0,0 +1,134 @@
+package kvfs_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvfs"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+// testDir holds directories of all stores created by the tests.
+var testDir string
+
+func TestMain(m *testing.M) {
+	var err error
+	testDir, err = os.MkdirTemp("", "kvfs-test-*")
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
+func newStore[V any]() (kv.Store[string, V], error) {
+	dir, err := os.MkdirTemp(testDir, "store-*")
+	if err != nil {
+		return nil, err
+	}
+	return kvfs.New[string, V](kvfs.DefaultOptions[V](dir))
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newStore)
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	// values are stored as is, as JSON replaces invalid UTF-8 of fuzzed values
+	testsuite.FuzzPrefixBytes(t, func() (kv.Store[string, string], error) {
+		dir, err := os.MkdirTemp(testDir, "store-*")
+		if err != nil {
+			return nil, err
+		}
+		opts := kvfs.DefaultOptions[string](dir)
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvfs.New[string, string](opts)
+	})
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newStore)
+}
+
+func TestLayout(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	opts := kvfs.DefaultOptions[string](t.TempDir())
+	opts.Codec = kv.CodecBytes[string]{}
+	s, err := kvfs.New[string, string](opts)
+	require.NoError(err)
+
+	for _, k := range []string{"app", "app/db/url", "app/.env", "x.d", "\xff/"} {
+		require.NoError(s.Set(ctx, k, "value of "+k))
+	}
+
+	files := []string{}
+	err = fs.WalkDir(s.FS(), ".", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(err)
+	require.ElementsMatch([]string{"app", "app.d/db.d/url", "app.d/%2Eenv", "x%2Ed", "%FF.d/%"}, files)
+
+	data, err := fs.ReadFile(s.FS(), "app.d/db.d/url")
+	require.NoError(err)
+	require.Equal("value of app/db/url", string(data))
+
+	keys := []string{}
+	err = s.RangeKeysWithPrefix(ctx, "app/", func(k string) error {
+		keys = append(keys, k)
+		return nil
+	})
+	require.NoError(err)
+	require.ElementsMatch([]string{"app/db/url", "app/.env"}, keys)
+
+	require.NoError(s.Delete(ctx, "app/db/url"))
+	_, err = fs.Stat(s.FS(), "app.d/db.d")
+	require.ErrorIs(err, fs.ErrNotExist)
+	_, err = fs.Stat(s.FS(), "app.d")
+	require.NoError(err)
+}
+
+func TestLongKeys(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	s, err := kvfs.New[string, string](kvfs.DefaultOptions[string](t.TempDir()))
+	require.NoError(err)
+
+	long := strings.Repeat("a.%", 300)
+	keys := []string{long, long + "/" + long, long + "b"}
+	for _, k := range keys {
+		require.NoError(s.Set(ctx, k, k))
+	}
+
+	for _, k := range keys {
+		v, err := s.Get(ctx, k)
+		require.NoError(err)
+		require.Equal(k, v)
+	}
+
+	vals := map[string]string{}
+	err = s.RangeWithPrefix(ctx, long, func(k, v string) error {
+		vals[k] = v
+		return nil
+	})
+	require.NoError(err)
+	require.Len(vals, 3)
+
+	for _, k := range keys {
+		require.NoError(s.Delete(ctx, k))
+	}
+	entries, err := fs.ReadDir(s.FS(), ".")
+	require.NoError(err)
+	require.Empty(entries)
+}
@@ -0,0 +1,18 @@
+module github.com/royalcat/kv/kvleveldb
+
+go 1.22.5
+
+require (
+	github.com/royalcat/kv v0.0.0-20240723125224-456de4e86ee6
+	github.com/royalcat/kv/testsuite v0.0.0-20240723125224-456de4e86ee6
+	github.com/stretchr/testify v1.9.0
+	github.com/syndtr/goleveldb v1.0.1-0.20220721030215-126854af5e6d
+)
+
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/golang/snappy v0.0.4 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,20 @@
+package kvleveldb
+
+import (
+	"github.com/royalcat/kv"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+type Options[V any] struct {
+	Codec          kv.Codec[V]
+	Dir            string
+	LevelDBOptions *opt.Options
+}
+
+func DefaultOptions[V any](dir string) Options[V] {
+	return Options[V]{
+		Codec:          kv.CodecJSON[V]{},
+		Dir:            dir,
+		LevelDBOptions: &opt.Options{},
+	}
+}
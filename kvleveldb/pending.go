@@ -0,0 +1,73 @@
+package kvleveldb
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// pending holds writes of an update transaction,
+// they are collected in a batch for the commit and indexed to be visible for reads of the transaction.
+type pending struct {
+	batch  *leveldb.Batch
+	writes map[string]write
+}
+
+type write struct {
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+func newPending() *pending {
+	return &pending{
+		batch:  new(leveldb.Batch),
+		writes: map[string]write{},
+	}
+}
+
+func (p *pending) put(k, v []byte) {
+	p.batch.Put(k, v)
+	p.writes[string(k)] = write{key: bytes.Clone(k), value: v}
+}
+
+func (p *pending) delete(k []byte) {
+	p.batch.Delete(k)
+	p.writes[string(k)] = write{key: bytes.Clone(k), deleted: true}
+}
+
+// get returns the pending write of the key, ok is false when the key was not written in the transaction.
+func (p *pending) get(k []byte) (w write, ok bool) {
+	if p == nil {
+		return w, false
+	}
+	w, ok = p.writes[string(k)]
+	return w, ok
+}
+
+// sorted returns pending writes of keys in [lower, upper) in the iteration order.
+func (p *pending) sorted(lower, upper []byte, reverse bool) []write {
+	if p == nil {
+		return nil
+	}
+
+	writes := []write{}
+	for _, w := range p.writes {
+		if lower != nil && bytes.Compare(w.key, lower) < 0 {
+			continue
+		}
+		if upper != nil && bytes.Compare(w.key, upper) >= 0 {
+			continue
+		}
+		writes = append(writes, w)
+	}
+
+	slices.SortFunc(writes, func(a, b write) int {
+		if reverse {
+			return bytes.Compare(b.key, a.key)
+		}
+		return bytes.Compare(a.key, b.key)
+	})
+	return writes
+}
@@ -0,0 +1,172 @@
+package kvleveldb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/royalcat/kv"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+var errReadOnlyTransaction = errors.New("transaction is read-only")
+
+var syncWrite = &opt.WriteOptions{Sync: true}
+
+// New opens a leveldb database in the options directory.
+// An empty directory opens an in-memory database.
+func New[K kv.Bytes, V any](opts Options[V]) (*Store[K, V], error) {
+	var db *leveldb.DB
+	var err error
+	if opts.Dir == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), opts.LevelDBOptions)
+	} else {
+		db, err = leveldb.OpenFile(opts.Dir, opts.LevelDBOptions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store[K, V]{
+		DB:      db,
+		Options: opts,
+	}, nil
+}
+
+// Store is a kv.Store on top of a leveldb database.
+//
+// Leveldb has no conflict detection, so writes through the store are serialized with an update transaction,
+// making Edit and transactions isolated from other writes through the same store.
+type Store[K kv.Bytes, V any] struct {
+	DB      *leveldb.DB
+	Options Options[V]
+
+	// wmu serializes writes and update transactions
+	wmu sync.Mutex
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+var _ kv.StoreCount[string] = (*Store[string, string])(nil)
+var _ kv.TransactionalStore[string, string] = (*Store[string, string])(nil)
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	return s.DB.Close()
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	data, err := s.Options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.DB.Put([]byte(k), data, syncWrite)
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(s.DB, nil, []byte(k), s.Options.Codec)
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return s.DB.Delete([]byte(k), syncWrite)
+}
+
+// Edit implements kv.Store.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	v, err := get(s.DB, nil, []byte(k), s.Options.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	data, err := s.Options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.DB.Put([]byte(k), data, syncWrite)
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, nil, nil, s.Options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, nil, []byte(prefix), s.Options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *Store[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, s.DB, nil, order, s.Options.Codec, iter)
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, nil, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, []byte(prefix), iter)
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	return s.DB.Has([]byte(k), nil)
+}
+
+// Count implements kv.StoreCount.
+func (s *Store[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	return count(ctx, s.DB, []byte(prefix))
+}
+
+// Transaction implements kv.TransactionalStore.
+//
+// A read-only transaction reads from a snapshot.
+// An update transaction reads from a snapshot overlaid with its own writes,
+// which are collected in a batch written atomically on Close.
+// It holds the store write lock until then, so the store itself must not be written
+// while a transaction is open in the same goroutine.
+func (s *Store[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	if update {
+		s.wmu.Lock()
+	}
+
+	snap, err := s.DB.GetSnapshot()
+	if err != nil {
+		if update {
+			s.wmu.Unlock()
+		}
+		return nil, err
+	}
+
+	tx := &transaction[K, V]{
+		db:      s.DB,
+		snap:    snap,
+		options: s.Options,
+	}
+	if update {
+		tx.pending = newPending()
+		tx.unlock = s.wmu.Unlock
+	}
+	return tx, nil
+}
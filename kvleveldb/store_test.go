@@ -0,0 +1,108 @@
+package kvleveldb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvleveldb"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemory[V any]() (kv.Store[string, V], error) {
+	return kvleveldb.New[string, V](kvleveldb.DefaultOptions[V](""))
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newMemory)
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	testsuite.FuzzPrefixBytes(t, newMemory)
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newMemory)
+}
+
+func TestPersistent(t *testing.T) {
+	testsuite.GoldenStrings(t, func() (kv.Store[string, string], error) {
+		opts := kvleveldb.DefaultOptions[string](t.TempDir())
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvleveldb.New[string, string](opts)
+	})
+}
+
+func newMemoryRaw() (*kvleveldb.Store[string, []byte], error) {
+	opts := kvleveldb.DefaultOptions[[]byte]("")
+	opts.Codec = kv.CodecBytes[[]byte]{}
+	return kvleveldb.New[string, []byte](opts)
+}
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	opts := kvleveldb.DefaultOptions[string]("")
+	opts.Codec = kv.CodecBytes[string]{}
+	s, err := kvleveldb.New[string, string](opts)
+	require.NoError(err)
+	defer s.Close(ctx)
+
+	for _, k := range []string{"a", "c", "e"} {
+		require.NoError(s.Set(ctx, k, "stored-"+k))
+	}
+
+	tx, err := s.Transaction(true)
+	require.NoError(err)
+	require.NoError(tx.Set(ctx, "b", "pending-b"))
+	require.NoError(tx.Set(ctx, "c", "pending-c"))
+	require.NoError(tx.Delete(ctx, "e"))
+	require.NoError(tx.Set(ctx, "f", "pending-f"))
+
+	v, err := tx.Get(ctx, "c")
+	require.NoError(err)
+	require.Equal("pending-c", v)
+	_, err = tx.Get(ctx, "e")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+
+	collect := func(store kv.StoreOrdered[string, string], reverse bool) []string {
+		items := []string{}
+		err := store.RangeOrdered(ctx, kv.Order[string]{Reverse: reverse}, func(k, v string) error {
+			items = append(items, k+"="+v)
+			return nil
+		})
+		require.NoError(err)
+		return items
+	}
+
+	expected := []string{"a=stored-a", "b=pending-b", "c=pending-c", "f=pending-f"}
+	require.Equal(expected, collect(tx.(kv.StoreOrdered[string, string]), false))
+	require.Equal([]string{"f=pending-f", "c=pending-c", "b=pending-b", "a=stored-a"}, collect(tx.(kv.StoreOrdered[string, string]), true))
+
+	// a read-only transaction reads from a snapshot taken before the commit
+	ro, err := s.Transaction(false)
+	require.NoError(err)
+
+	require.NoError(tx.Close(ctx))
+	require.Equal(expected, collect(s, false))
+
+	require.Equal([]string{"a=stored-a", "c=stored-c", "e=stored-e"}, collect(ro.(kv.StoreOrdered[string, string]), false))
+	require.Error(ro.Set(ctx, "a", "x"))
+	require.NoError(ro.Close(ctx))
+}
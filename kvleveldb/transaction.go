@@ -0,0 +1,97 @@
+package kvleveldb
+
+import (
+	"context"
+
+	"github.com/royalcat/kv"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type transaction[K kv.Bytes, V any] struct {
+	db   *leveldb.DB
+	snap *leveldb.Snapshot
+	// pending is nil for read-only transactions
+	pending *pending
+	unlock  func()
+	options Options[V]
+	closed  bool
+}
+
+var _ kv.Store[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*transaction[string, string])(nil)
+
+// Close implements kv.Store, it commits an update transaction.
+func (t *transaction[K, V]) Close(ctx context.Context) error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	t.snap.Release()
+	if t.pending == nil {
+		return nil
+	}
+	defer t.unlock()
+
+	return t.db.Write(t.pending.batch, syncWrite)
+}
+
+// Set implements kv.Store.
+func (t *transaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	if t.pending == nil {
+		return errReadOnlyTransaction
+	}
+
+	data, err := t.options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	t.pending.put([]byte(k), data)
+	return nil
+}
+
+// Get implements kv.Store.
+func (t *transaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(t.snap, t.pending, []byte(k), t.options.Codec)
+}
+
+// Delete implements kv.Store.
+func (t *transaction[K, V]) Delete(ctx context.Context, k K) error {
+	if t.pending == nil {
+		return errReadOnlyTransaction
+	}
+	t.pending.delete([]byte(k))
+	return nil
+}
+
+// Edit implements kv.Store.
+func (t *transaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	if t.pending == nil {
+		return errReadOnlyTransaction
+	}
+
+	v, err := get(t.snap, t.pending, []byte(k), t.options.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return t.Set(ctx, k, v)
+}
+
+// Range implements kv.Store.
+func (t *transaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.snap, t.pending, nil, t.options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *transaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.snap, t.pending, []byte(prefix), t.options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *transaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, t.snap, t.pending, order, t.options.Codec, iter)
+}
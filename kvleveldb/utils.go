@@ -0,0 +1,155 @@
+package kvleveldb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/royalcat/kv"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// reader is implemented by both *leveldb.DB and *leveldb.Snapshot.
+type reader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// get reads the key from pending writes first, p may be nil.
+func get[V any](r reader, p *pending, k []byte, codec kv.Codec[V]) (V, error) {
+	var v V
+
+	data, err := getRaw(r, p, k)
+	if err != nil {
+		return v, err
+	}
+	err = codec.Unmarshal(data, &v)
+	return v, err
+}
+
+func getRaw(r reader, p *pending, k []byte) ([]byte, error) {
+	if w, ok := p.get(k); ok {
+		if w.deleted {
+			return nil, kv.ErrKeyNotFound
+		}
+		return w.value, nil
+	}
+
+	data, err := r.Get(k, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, kv.ErrKeyNotFound
+	}
+	return data, err
+}
+
+// rangeBounds iterates over keys in [lower, upper), nil bound means the range is unbounded on that side.
+// Pending writes are merged into the iteration, p may be nil.
+// Keys and values passed to the iterator are copies, as the iterator reuses its buffers.
+func rangeBounds(ctx context.Context, r reader, p *pending, lower, upper []byte, reverse bool, iter func(k, v []byte) error) error {
+	it := r.NewIterator(&util.Range{Start: lower, Limit: upper}, nil)
+	defer it.Release()
+
+	writes := p.sorted(lower, upper, reverse)
+
+	next, valid := it.Next, it.First()
+	if reverse {
+		next, valid = it.Prev, it.Last()
+	}
+	for valid || len(writes) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var k, v []byte
+		switch {
+		case len(writes) == 0:
+			k, v = slices.Clone(it.Key()), slices.Clone(it.Value())
+			valid = next()
+		case !valid || before(writes[0].key, it.Key(), reverse):
+			w := writes[0]
+			writes = writes[1:]
+			if w.deleted {
+				continue
+			}
+			k, v = w.key, w.value
+		default:
+			// the stored value is shadowed by a pending write of the same key
+			if bytes.Equal(writes[0].key, it.Key()) {
+				valid = next()
+				continue
+			}
+			k, v = slices.Clone(it.Key()), slices.Clone(it.Value())
+			valid = next()
+		}
+
+		// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+		if err := iter(k, v); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// before reports whether a comes before b in the iteration order.
+func before(a, b []byte, reverse bool) bool {
+	if reverse {
+		return bytes.Compare(a, b) > 0
+	}
+	return bytes.Compare(a, b) < 0
+}
+
+func rangeValues[K kv.Bytes, V any](ctx context.Context, r reader, p *pending, lower, upper []byte, reverse bool, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeBounds(ctx, r, p, lower, upper, reverse, func(k, data []byte) error {
+		var v V
+		if err := codec.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		return iter(K(k), v)
+	})
+}
+
+func rangePrefix[K kv.Bytes, V any](ctx context.Context, r reader, p *pending, prefix []byte, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, r, p, nilIfEmpty(prefix), prefixEnd(prefix), false, codec, iter)
+}
+
+func rangeOrdered[K kv.Bytes, V any](ctx context.Context, r reader, p *pending, order kv.Order[K], codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, r, p, nilIfEmpty([]byte(order.Min)), nilIfEmpty([]byte(order.Max)), order.Reverse, codec, iter)
+}
+
+func rangeKeys[K kv.Bytes](ctx context.Context, r reader, prefix []byte, iter kv.KeyIter[K]) error {
+	return rangeBounds(ctx, r, nil, nilIfEmpty(prefix), prefixEnd(prefix), false, func(k, _ []byte) error {
+		return iter(K(k))
+	})
+}
+
+func count(ctx context.Context, r reader, prefix []byte) (int, error) {
+	n := 0
+	err := rangeBounds(ctx, r, nil, nilIfEmpty(prefix), prefixEnd(prefix), false, func(_, _ []byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// prefixEnd returns the smallest key greater than all keys with the prefix, nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func nilIfEmpty(k []byte) []byte {
+	if len(k) == 0 {
+		return nil
+	}
+	return k
+}
@@ -0,0 +1,57 @@
+package kvmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+// NewCounter creates an in-memory kv.Counter.
+// When ttl is positive, it is applied when a counter is created, further increments keep the original expiration.
+func NewCounter[K kv.Bytes](ttl time.Duration) kv.Counter[K] {
+	return &counter[K]{
+		ttl:      ttl,
+		counters: map[string]kv.CounterValue{},
+	}
+}
+
+type counter[K kv.Bytes] struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	counters map[string]kv.CounterValue
+}
+
+var _ kv.Counter[string] = (*counter[string])(nil)
+
+// Incr implements kv.Counter.
+func (c *counter[K]) Incr(ctx context.Context, k K, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v := c.counters[string(k)].Incr(delta, c.ttl, time.Now())
+	c.counters[string(k)] = v
+	return v.Value, nil
+}
+
+// Decr implements kv.Counter.
+func (c *counter[K]) Decr(ctx context.Context, k K, delta int64) (int64, error) {
+	return c.Incr(ctx, k, -delta)
+}
+
+// GetCounter implements kv.Counter.
+func (c *counter[K]) GetCounter(ctx context.Context, k K) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.counters[string(k)]
+	if !ok {
+		return 0, nil
+	}
+	if v.Expired(time.Now()) {
+		delete(c.counters, string(k))
+		return 0, nil
+	}
+	return v.Value, nil
+}
@@ -0,0 +1,22 @@
+package kvmemory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestCounter(t *testing.T) {
+	testsuite.GoldenCounter(t, func(ttl time.Duration) (kv.Counter[string], error) {
+		return kvmemory.NewCounter[string](ttl), nil
+	})
+}
+
+func TestStoreCounter(t *testing.T) {
+	testsuite.GoldenCounter(t, func(ttl time.Duration) (kv.Counter[string], error) {
+		return kv.NewStoreCounter(kvmemory.NewMemoryKV[string, kv.CounterValue](), ttl), nil
+	})
+}
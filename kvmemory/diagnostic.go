@@ -0,0 +1,160 @@
+package kvmemory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrDeadlock = errors.New("deadlock detected")
+
+// LockHolder describes a held lock.
+type LockHolder struct {
+	Key string
+	// Goroutine is the ID of the goroutine which acquired the lock.
+	Goroutine int64
+	// Stack is the stack trace of the acquisition.
+	Stack string
+	Since time.Time
+	Held  time.Duration
+}
+
+// WithDiagnostics makes locks track their holders and goroutines waiting for them.
+//
+// Lock returns ErrDeadlock instead of waiting if the wait would close a cycle of goroutines waiting for each other,
+// including a goroutine locking a key it already holds. Tracking captures a stack trace on every acquisition,
+// so the diagnostic mode is meant for tests and debugging.
+func WithDiagnostics() LocksOption {
+	return func(l *locksOptions) {
+		l.diagnostics = true
+	}
+}
+
+type diagnostics struct {
+	mu      sync.Mutex
+	holders map[string]LockHolder
+	// waiting maps goroutines to the keys they are waiting for
+	waiting map[int64]string
+}
+
+func newDiagnostics() *diagnostics {
+	return &diagnostics{
+		holders: map[string]LockHolder{},
+		waiting: map[int64]string{},
+	}
+}
+
+// wait registers the goroutine as waiting for the key, unless the wait closes a cycle.
+func (d *diagnostics) wait(g int64, k string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if cycle := d.findCycle(g, k); cycle != nil {
+		return fmt.Errorf("%w: %s", ErrDeadlock, formatCycle(g, k, cycle))
+	}
+	d.waiting[g] = k
+	return nil
+}
+
+// acquired ends the wait of the goroutine, it becomes the holder of the key if the lock was acquired.
+func (d *diagnostics) acquired(g int64, k string, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.waiting, g)
+	if ok {
+		d.holders[k] = LockHolder{
+			Key:       k,
+			Goroutine: g,
+			Stack:     string(debug.Stack()),
+			Since:     time.Now(),
+		}
+	}
+}
+
+// release removes the holder of the key if unlock succeeds. The mutex is held during unlock,
+// so the next holder of the key is recorded only after the previous one is removed.
+func (d *diagnostics) release(k string, unlock func() error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err := unlock()
+	if err == nil {
+		delete(d.holders, k)
+	}
+	return err
+}
+
+func (d *diagnostics) clear() {
+	d.mu.Lock()
+	clear(d.holders)
+	d.mu.Unlock()
+}
+
+func (d *diagnostics) heldLocks() []LockHolder {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	holders := make([]LockHolder, 0, len(d.holders))
+	for _, h := range d.holders {
+		h.Held = now.Sub(h.Since)
+		holders = append(holders, h)
+	}
+	slices.SortFunc(holders, func(a, b LockHolder) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return holders
+}
+
+// findCycle returns holders of the wait-for chain starting at the key if it leads back to the goroutine,
+// it must be called with the mutex held.
+func (d *diagnostics) findCycle(g int64, k string) []LockHolder {
+	chain := []LockHolder{}
+	for len(chain) <= len(d.holders) {
+		h, ok := d.holders[k]
+		if !ok {
+			return nil
+		}
+		chain = append(chain, h)
+		if h.Goroutine == g {
+			return chain
+		}
+		k, ok = d.waiting[h.Goroutine]
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
+func formatCycle(g int64, k string, cycle []LockHolder) string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "goroutine %d waits for %q", g, k)
+	for i, h := range cycle {
+		fmt.Fprintf(&b, ", held by goroutine %d", h.Goroutine)
+		if i < len(cycle)-1 {
+			fmt.Fprintf(&b, " waiting for %q", cycle[i+1].Key)
+		}
+	}
+	return b.String()
+}
+
+// goroutineID parses the ID of the current goroutine from its stack trace header "goroutine N [running]:".
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
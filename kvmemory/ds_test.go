@@ -0,0 +1,21 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestDataStructures(t *testing.T) {
+	testsuite.GoldenDataStructures(t, func() (kv.Store[string, []byte], error) {
+		return kvmemory.NewMemoryKV[string, []byte](), nil
+	})
+}
+
+func TestOrderedDataStructures(t *testing.T) {
+	testsuite.GoldenDataStructures(t, func() (kv.Store[string, []byte], error) {
+		return kvmemory.NewOrderedKV[string, []byte](), nil
+	})
+}
@@ -0,0 +1,15 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestElection(t *testing.T) {
+	testsuite.GoldenElection(t, func() (kv.Store[string, string], error) {
+		return kvmemory.NewMemoryKV[string, string](), nil
+	})
+}
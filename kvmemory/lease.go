@@ -0,0 +1,115 @@
+package kvmemory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+// NewLeaseLocks creates in-memory kv.LeaseLocks, waiters are woken up as soon as a lease is released or expires.
+func NewLeaseLocks[K kv.Bytes]() kv.LeaseLocks[K] {
+	return &leaseLocks[K]{
+		records: map[string]kv.LeaseRecord{},
+		leases:  map[kv.Lease]struct{}{},
+		changed: make(chan struct{}),
+	}
+}
+
+type leaseLocks[K kv.Bytes] struct {
+	mu      sync.Mutex
+	records map[string]kv.LeaseRecord
+	leases  map[kv.Lease]struct{}
+	// changed is closed and replaced when any lease is released
+	changed chan struct{}
+}
+
+var _ kv.LeaseLocks[string] = (*leaseLocks[string])(nil)
+
+// Lock implements kv.LeaseLocks.
+func (l *leaseLocks[K]) Lock(ctx context.Context, key K, ttl time.Duration) (kv.Lease, error) {
+	if ttl <= 0 {
+		return nil, kv.ErrInvalidLeaseTTL
+	}
+	k := string(key)
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		r := l.records[k]
+		if !r.Held(now) {
+			token := r.Token + 1
+			l.records[k] = kv.LeaseRecord{Token: token, ExpiresAt: now.Add(ttl)}
+			lease := l.newLease(k, token, ttl)
+			l.mu.Unlock()
+			return lease, nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		timer := time.NewTimer(r.ExpiresAt.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-changed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// newLease must be called with the mutex held.
+func (l *leaseLocks[K]) newLease(k string, token uint64, ttl time.Duration) kv.Lease {
+	var lease kv.Lease
+	lease = kv.NewLease(token, ttl,
+		func(ctx context.Context) error {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			r := l.records[k]
+			if r.Token != token || r.ExpiresAt.IsZero() {
+				return kv.ErrLeaseLost
+			}
+			l.records[k] = kv.LeaseRecord{Token: token, ExpiresAt: time.Now().Add(ttl)}
+			return nil
+		},
+		func(ctx context.Context) error {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			delete(l.leases, lease)
+			r := l.records[k]
+			if r.Token != token || !r.Held(time.Now()) {
+				return kv.ErrLeaseLost
+			}
+			l.records[k] = kv.LeaseRecord{Token: token}
+			close(l.changed)
+			l.changed = make(chan struct{})
+			return nil
+		},
+	)
+	l.leases[lease] = struct{}{}
+	return lease
+}
+
+// Close implements kv.LeaseLocks.
+func (l *leaseLocks[K]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	leases := make([]kv.Lease, 0, len(l.leases))
+	for lease := range l.leases {
+		leases = append(leases, lease)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, lease := range leases {
+		err := lease.Release(ctx)
+		if err != nil && !errors.Is(err, kv.ErrLeaseLost) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package kvmemory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseLocks(t *testing.T) {
+	testsuite.GoldenLeaseLocks(t, func() (kv.LeaseLocks[string], error) {
+		return kvmemory.NewLeaseLocks[string](), nil
+	})
+}
+
+func TestStoreLeaseLocks(t *testing.T) {
+	testsuite.GoldenLeaseLocks(t, func() (kv.LeaseLocks[string], error) {
+		return kv.NewStoreLeaseLocks(kvmemory.NewMemoryKV[string, kv.LeaseRecord](), 10*time.Millisecond), nil
+	})
+}
+
+func TestStoreLeaseLost(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store := kvmemory.NewMemoryKV[string, kv.LeaseRecord]()
+	locks := kv.NewStoreLeaseLocks(store, 10*time.Millisecond)
+
+	lease, err := locks.Lock(ctx, "key", 150*time.Millisecond)
+	require.NoError(err)
+
+	// another holder took over the lease
+	require.NoError(store.Set(ctx, "key", kv.LeaseRecord{Token: lease.Token() + 1, ExpiresAt: time.Now().Add(time.Minute)}))
+
+	select {
+	case <-lease.Lost():
+	case <-time.After(time.Second):
+		require.Fail("lease must be lost")
+	}
+	require.ErrorIs(lease.Release(ctx), kv.ErrLeaseLost)
+}
+
+func TestLeaseFailedRenewal(t *testing.T) {
+	const ttl = 300 * time.Millisecond
+	renewals := map[string]func(ctx context.Context) error{
+		"Error": func(ctx context.Context) error {
+			return errors.New("store is unavailable")
+		},
+		"Hang": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	for name, renew := range renewals {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			start := time.Now()
+			lease := kv.NewLease(1, ttl, renew, func(ctx context.Context) error { return nil })
+
+			select {
+			case <-lease.Lost():
+			case <-time.After(time.Second):
+				require.Fail("lease must be lost")
+			}
+			require.LessOrEqual(time.Since(start), ttl, "lease must be lost before it expires")
+			require.ErrorIs(lease.Release(context.Background()), kv.ErrLeaseLost)
+		})
+	}
+}
@@ -8,57 +8,144 @@ import (
 	"github.com/royalcat/kv"
 )
 
-type locks[K kv.Bytes] struct {
+// LocksOption configures locks created by NewLocks.
+type LocksOption func(*locksOptions)
+
+type locksOptions struct {
+	diagnostics bool
+}
+
+// Locks keeps a channel with a single slot per key, so waiting for a lock can be cancelled.
+type Locks[K kv.Bytes] struct {
 	mu    sync.RWMutex
-	locks map[string]*sync.Mutex
+	locks map[string]chan struct{}
+
+	// diag is nil unless the locks are created WithDiagnostics
+	diag *diagnostics
 }
 
-func NewLocks[K kv.Bytes]() kv.Locks[K] {
-	return &locks[K]{
-		locks: map[string]*sync.Mutex{},
+// NewLocks creates in-memory kv.Locks.
+func NewLocks[K kv.Bytes](opts ...LocksOption) *Locks[K] {
+	o := locksOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l := &Locks[K]{
+		locks: map[string]chan struct{}{},
 	}
+	if o.diagnostics {
+		l.diag = newDiagnostics()
+	}
+	return l
 }
 
-var _ kv.Locks[string] = (*locks[string])(nil)
+var _ kv.Locks[string] = (*Locks[string])(nil)
 
 // Lock implements kv.Locks.
-func (l *locks[K]) Lock(ctx context.Context, key K) error {
+func (l *Locks[K]) Lock(ctx context.Context, key K) error {
 	k := string(key)
+	if l.diag == nil {
+		return l.lockWait(ctx, k)
+	}
 
-	l.mu.RLock()
-	mu, ok := l.locks[k]
-	l.mu.RUnlock()
-	if !ok {
-		l.mu.Lock()
-		mu = &sync.Mutex{}
-		l.locks[k] = mu
-		l.mu.Unlock()
+	g := goroutineID()
+	if err := l.diag.wait(g, k); err != nil {
+		return err
+	}
+	err := l.lockWait(ctx, k)
+	l.diag.acquired(g, k, err == nil)
+	return err
+}
+
+func (l *Locks[K]) lockWait(ctx context.Context, k string) error {
+	select {
+	case l.lock(k) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryLock implements kv.Locks.
+func (l *Locks[K]) TryLock(ctx context.Context, key K) (bool, error) {
+	select {
+	case l.lock(string(key)) <- struct{}{}:
+		if l.diag != nil {
+			l.diag.acquired(goroutineID(), string(key), true)
+		}
+		return true, nil
+	default:
+		return false, nil
 	}
-	mu.Lock()
-	return nil
 }
 
 // Unlock implements kv.Locks.
-func (l *locks[K]) Unlock(ctx context.Context, key K) error {
+func (l *Locks[K]) Unlock(ctx context.Context, key K) error {
 	k := string(key)
+	if l.diag == nil {
+		return l.unlock(k)
+	}
+	return l.diag.release(k, func() error { return l.unlock(k) })
+}
 
+func (l *Locks[K]) unlock(k string) error {
 	l.mu.RLock()
 	mu, ok := l.locks[k]
 	l.mu.RUnlock()
 
 	if !ok {
-		return fmt.Errorf("lock not found for key: %v", key)
+		return fmt.Errorf("lock not found for key: %v", k)
+	}
+	select {
+	case <-mu:
+		return nil
+	default:
+		return fmt.Errorf("lock is not locked for key: %v", k)
 	}
-	mu.Unlock()
-	return nil
 }
 
-func (l *locks[K]) Close(ctx context.Context) error {
+// Close implements kv.Locks.
+func (l *Locks[K]) Close(ctx context.Context) error {
+	if l.diag != nil {
+		l.diag.clear()
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	for _, mu := range l.locks {
-		mu.Unlock()
+		select {
+		case <-mu:
+		default:
+		}
 	}
 	return nil
 }
+
+// HeldLocks returns a snapshot of currently held locks ordered by key,
+// it is empty unless the locks are created WithDiagnostics.
+func (l *Locks[K]) HeldLocks() []LockHolder {
+	if l.diag == nil {
+		return nil
+	}
+	return l.diag.heldLocks()
+}
+
+func (l *Locks[K]) lock(k string) chan struct{} {
+	l.mu.RLock()
+	mu, ok := l.locks[k]
+	l.mu.RUnlock()
+	if ok {
+		return mu
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	mu, ok = l.locks[k]
+	if !ok {
+		mu = make(chan struct{}, 1)
+		l.locks[k] = mu
+	}
+	return mu
+}
@@ -1,11 +1,15 @@
 package kvmemory_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/royalcat/kv"
 	"github.com/royalcat/kv/kvmemory"
 	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLocks(t *testing.T) {
@@ -13,3 +17,52 @@ func TestLocks(t *testing.T) {
 		return kvmemory.NewLocks[string](), nil
 	})
 }
+
+func TestLockMany(t *testing.T) {
+	testsuite.GoldenLockMany(t, func() (kv.Locks[string], error) {
+		return kvmemory.NewLocks[string](), nil
+	})
+}
+
+func TestDiagnosticLocks(t *testing.T) {
+	testsuite.GoldenLocks(t, func() (kv.Locks[string], error) {
+		return kvmemory.NewLocks[string](kvmemory.WithDiagnostics()), nil
+	})
+}
+
+func TestDiagnosticLocksDeadlock(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	locks := kvmemory.NewLocks[string](kvmemory.WithDiagnostics())
+
+	require.NoError(locks.Lock(ctx, "a"))
+	require.ErrorIs(locks.Lock(ctx, "a"), kvmemory.ErrDeadlock)
+
+	holders := locks.HeldLocks()
+	require.Len(holders, 1)
+	require.Equal("a", holders[0].Key)
+	require.Contains(holders[0].Stack, "TestDiagnosticLocksDeadlock")
+
+	// the other goroutine holds "b" and waits for "a"
+	locked := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		err := locks.Lock(ctx, "b")
+		close(locked)
+		if err == nil {
+			err = locks.Lock(ctx, "a")
+			err = errors.Join(err, locks.Unlock(ctx, "a"), locks.Unlock(ctx, "b"))
+		}
+		done <- err
+	}()
+	<-locked
+	time.Sleep(50 * time.Millisecond)
+
+	err := locks.Lock(ctx, "b")
+	require.ErrorIs(err, kvmemory.ErrDeadlock)
+	require.ErrorContains(err, `waits for "b"`)
+
+	require.NoError(locks.Unlock(ctx, "a"))
+	require.NoError(<-done)
+	require.Empty(locks.HeldLocks())
+}
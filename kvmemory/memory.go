@@ -104,3 +104,33 @@ func (m *memoryKV[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.
 	}
 	return nil
 }
+
+var _ kv.StoreHas[string] = (*memoryKV[string, string])(nil)
+var _ kv.StoreCount[string] = (*memoryKV[string, string])(nil)
+
+// Has implements kv.StoreHas.
+func (m *memoryKV[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	_, found := m.data[string(k)]
+	return found, nil
+}
+
+// Count implements kv.StoreCount.
+func (m *memoryKV[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if len(prefix) == 0 {
+		return len(m.data), nil
+	}
+
+	n := 0
+	for k := range m.data {
+		if strings.HasPrefix(k, string(prefix)) {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -13,3 +13,15 @@ func TestGolden(t *testing.T) {
 		return kvmemory.NewMemoryKV[string, string](), nil
 	})
 }
+
+func TestUpdate(t *testing.T) {
+	testsuite.GoldenUpdate(t, func() (kv.Store[string, string], error) {
+		return kvmemory.NewMemoryKV[string, string](), nil
+	})
+}
+
+func TestPageEmptyKey(t *testing.T) {
+	testsuite.GoldenPageEmptyKey(t, func() (kv.Store[string, string], error) {
+		return kvmemory.NewMemoryKV[string, string](), nil
+	})
+}
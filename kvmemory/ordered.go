@@ -0,0 +1,288 @@
+package kvmemory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/royalcat/kv"
+)
+
+var errReadOnlyTransaction = errors.New("transaction is read-only")
+
+// NewOrderedKV creates an in-memory store keeping keys sorted,
+// it implements kv.StoreOrdered and kv.TransactionalStore.
+func NewOrderedKV[K kv.Bytes, V any]() *OrderedKV[K, V] {
+	return &OrderedKV[K, V]{
+		data: orderedData[V]{
+			values: map[string]V{},
+		},
+	}
+}
+
+type OrderedKV[K kv.Bytes, V any] struct {
+	m    sync.RWMutex
+	data orderedData[V]
+}
+
+var _ kv.Store[string, string] = (*OrderedKV[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*OrderedKV[string, string])(nil)
+var _ kv.TransactionalStore[string, string] = (*OrderedKV[string, string])(nil)
+
+// Close implements kv.Store.
+func (m *OrderedKV[K, V]) Close(ctx context.Context) error {
+	return nil
+}
+
+// Delete implements kv.Store.
+func (m *OrderedKV[K, V]) Delete(ctx context.Context, k K) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.data.delete(string(k))
+	return nil
+}
+
+// Edit implements kv.Store.
+func (m *OrderedKV[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	return m.data.edit(ctx, string(k), edit)
+}
+
+// Get implements kv.Store.
+func (m *OrderedKV[K, V]) Get(ctx context.Context, k K) (V, error) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	return m.data.get(string(k))
+}
+
+// Set implements kv.Store.
+func (m *OrderedKV[K, V]) Set(ctx context.Context, k K, v V) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.data.set(string(k), v)
+	return nil
+}
+
+// Range implements kv.Store.
+func (m *OrderedKV[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	return m.data.rangeOrdered(ctx, kv.Order[string]{}, castIter(iter))
+}
+
+// RangeWithPrefix implements kv.Store.
+func (m *OrderedKV[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	return m.data.rangePrefix(ctx, string(prefix), castIter(iter))
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (m *OrderedKV[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	return m.data.rangeOrdered(ctx, stringOrder(order), castIter(iter))
+}
+
+// Transaction implements kv.TransactionalStore.
+//
+// Transactions are serialized with the store lock, which is held until the transaction is closed,
+// so the store itself must not be used while a transaction is open in the same goroutine.
+// Writes are applied immediately, as kv.Store has no rollback.
+func (m *OrderedKV[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	if update {
+		m.m.Lock()
+	} else {
+		m.m.RLock()
+	}
+
+	return &orderedTransaction[K, V]{
+		store:  m,
+		update: update,
+	}, nil
+}
+
+type orderedTransaction[K kv.Bytes, V any] struct {
+	store  *OrderedKV[K, V]
+	update bool
+	closed bool
+}
+
+var _ kv.Store[string, string] = (*orderedTransaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*orderedTransaction[string, string])(nil)
+
+// Close implements kv.Store.
+func (t *orderedTransaction[K, V]) Close(ctx context.Context) error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if t.update {
+		t.store.m.Unlock()
+	} else {
+		t.store.m.RUnlock()
+	}
+	return nil
+}
+
+// Delete implements kv.Store.
+func (t *orderedTransaction[K, V]) Delete(ctx context.Context, k K) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+
+	t.store.data.delete(string(k))
+	return nil
+}
+
+// Edit implements kv.Store.
+func (t *orderedTransaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+
+	return t.store.data.edit(ctx, string(k), edit)
+}
+
+// Get implements kv.Store.
+func (t *orderedTransaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return t.store.data.get(string(k))
+}
+
+// Set implements kv.Store.
+func (t *orderedTransaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+
+	t.store.data.set(string(k), v)
+	return nil
+}
+
+// Range implements kv.Store.
+func (t *orderedTransaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return t.store.data.rangeOrdered(ctx, kv.Order[string]{}, castIter(iter))
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *orderedTransaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return t.store.data.rangePrefix(ctx, string(prefix), castIter(iter))
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *orderedTransaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return t.store.data.rangeOrdered(ctx, stringOrder(order), castIter(iter))
+}
+
+// orderedData is a map with a sorted slice of its keys, it must be guarded by the owner.
+type orderedData[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+func (d *orderedData[V]) get(k string) (V, error) {
+	v, found := d.values[k]
+	if !found {
+		return v, kv.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (d *orderedData[V]) set(k string, v V) {
+	if _, found := d.values[k]; !found {
+		i, _ := slices.BinarySearch(d.keys, k)
+		d.keys = slices.Insert(d.keys, i, k)
+	}
+	d.values[k] = v
+}
+
+func (d *orderedData[V]) delete(k string) {
+	if _, found := d.values[k]; !found {
+		return
+	}
+	i, _ := slices.BinarySearch(d.keys, k)
+	d.keys = slices.Delete(d.keys, i, i+1)
+	delete(d.values, k)
+}
+
+func (d *orderedData[V]) edit(ctx context.Context, k string, edit kv.Edit[V]) error {
+	v, found := d.values[k]
+	if !found {
+		return kv.ErrKeyNotFound
+	}
+	v, err := edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	d.values[k] = v
+	return nil
+}
+
+func (d *orderedData[V]) rangePrefix(ctx context.Context, prefix string, iter kv.Iter[string, V]) error {
+	from, _ := slices.BinarySearch(d.keys, prefix)
+	to := from
+	for to < len(d.keys) && strings.HasPrefix(d.keys[to], prefix) {
+		to++
+	}
+
+	return d.iterate(ctx, slices.Clone(d.keys[from:to]), iter)
+}
+
+func (d *orderedData[V]) rangeOrdered(ctx context.Context, order kv.Order[string], iter kv.Iter[string, V]) error {
+	from, to := 0, len(d.keys)
+	if order.Min != "" {
+		from, _ = slices.BinarySearch(d.keys, order.Min)
+	}
+	if order.Max != "" {
+		to, _ = slices.BinarySearch(d.keys, order.Max)
+	}
+	keys := slices.Clone(d.keys[from:max(from, to)])
+
+	if order.Reverse {
+		slices.Reverse(keys)
+	}
+
+	return d.iterate(ctx, keys, iter)
+}
+
+// iterate calls iter for the given keys, keys must be a copy, so the iterator may modify the data inside a transaction.
+func (d *orderedData[V]) iterate(ctx context.Context, keys []string, iter kv.Iter[string, V]) error {
+	for _, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		v, found := d.values[k]
+		if !found {
+			continue
+		}
+		if err := iter(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringOrder[K kv.Bytes](order kv.Order[K]) kv.Order[string] {
+	return kv.Order[string]{
+		Min:     string(order.Min),
+		Max:     string(order.Max),
+		Reverse: order.Reverse,
+	}
+}
+
+func castIter[K kv.Bytes, V any](iter kv.Iter[K, V]) kv.Iter[string, V] {
+	return func(k string, v V) error {
+		return iter(K(k), v)
+	}
+}
@@ -0,0 +1,25 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func newOrdered() (kv.Store[string, string], error) {
+	return kvmemory.NewOrderedKV[string, string](), nil
+}
+
+func TestOrderedGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newOrdered)
+}
+
+func FuzzOrderedPrefixBytes(t *testing.F) {
+	testsuite.FuzzPrefixBytes(t, newOrdered)
+}
+
+func TestOrderedPageEmptyKey(t *testing.T) {
+	testsuite.GoldenPageEmptyKey(t, newOrdered)
+}
@@ -0,0 +1,15 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		return kvmemory.NewOrderedKV[string, []byte](), nil
+	})
+}
@@ -0,0 +1,80 @@
+package kvmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv/ratelimit"
+)
+
+// NewTokenBucket creates an in-memory token bucket limiter, updating buckets in place without encoding.
+// Full buckets are indistinguishable from missing ones, so they are removed by [Limiter.Prune].
+func NewTokenBucket[K comparable](opts ratelimit.TokenBucketOptions) *Limiter[K, ratelimit.TokenBucketState] {
+	return &Limiter[K, ratelimit.TokenBucketState]{
+		clock:  opts.Clock,
+		states: map[K]ratelimit.TokenBucketState{},
+		take: func(s ratelimit.TokenBucketState, n int, now time.Time) (ratelimit.TokenBucketState, bool) {
+			return s.Take(opts, n, now)
+		},
+		idle: func(s ratelimit.TokenBucketState, now time.Time) bool {
+			s, _ = s.Take(opts, 0, now)
+			return s.Tokens >= float64(opts.Burst)
+		},
+	}
+}
+
+// NewSlidingWindow creates an in-memory sliding window limiter, updating windows in place without encoding.
+// Windows without events in the last two windows are removed by [Limiter.Prune].
+func NewSlidingWindow[K comparable](opts ratelimit.SlidingWindowOptions) *Limiter[K, ratelimit.SlidingWindowState] {
+	return &Limiter[K, ratelimit.SlidingWindowState]{
+		clock:  opts.Clock,
+		states: map[K]ratelimit.SlidingWindowState{},
+		take: func(s ratelimit.SlidingWindowState, n int, now time.Time) (ratelimit.SlidingWindowState, bool) {
+			return s.Take(opts, n, now)
+		},
+		idle: func(s ratelimit.SlidingWindowState, now time.Time) bool {
+			return !now.Before(s.Start.Add(2 * opts.Window))
+		},
+	}
+}
+
+// Limiter is an in-memory rate limiter, it is a fast path for limits local to the process.
+type Limiter[K comparable, S any] struct {
+	mu     sync.Mutex
+	clock  ratelimit.Clock
+	states map[K]S
+	take   func(s S, n int, now time.Time) (S, bool)
+	idle   func(s S, now time.Time) bool
+}
+
+var _ ratelimit.Limiter[string] = (*Limiter[string, ratelimit.TokenBucketState])(nil)
+
+// Allow implements ratelimit.Limiter.
+func (l *Limiter[K, S]) Allow(ctx context.Context, key K, n int) (bool, error) {
+	if n <= 0 {
+		return false, ratelimit.ErrInvalidN
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, allowed := l.take(l.states[key], n, l.clock.Now())
+	if allowed {
+		l.states[key] = s
+	}
+	return allowed, nil
+}
+
+// Prune removes states of keys which are in their initial state again, bounding memory used by inactive keys.
+func (l *Limiter[K, S]) Prune() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	for k, s := range l.states {
+		if l.idle(s, now) {
+			delete(l.states, k)
+		}
+	}
+}
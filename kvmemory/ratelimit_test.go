@@ -0,0 +1,68 @@
+package kvmemory_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/ratelimit"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	testsuite.GoldenTokenBucket(t, func(opts ratelimit.TokenBucketOptions) (ratelimit.Limiter[string], error) {
+		return kvmemory.NewTokenBucket[string](opts), nil
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	testsuite.GoldenSlidingWindow(t, func(opts ratelimit.SlidingWindowOptions) (ratelimit.Limiter[string], error) {
+		return kvmemory.NewSlidingWindow[string](opts), nil
+	})
+}
+
+func TestStoreTokenBucket(t *testing.T) {
+	testsuite.GoldenTokenBucket(t, func(opts ratelimit.TokenBucketOptions) (ratelimit.Limiter[string], error) {
+		return ratelimit.NewTokenBucket(kvmemory.NewMemoryKV[string, ratelimit.TokenBucketState](), opts), nil
+	})
+}
+
+func TestStoreSlidingWindow(t *testing.T) {
+	testsuite.GoldenSlidingWindow(t, func(opts ratelimit.SlidingWindowOptions) (ratelimit.Limiter[string], error) {
+		return ratelimit.NewSlidingWindow(kvmemory.NewOrderedKV[string, ratelimit.SlidingWindowState](), opts), nil
+	})
+}
+
+func TestLockedTokenBucket(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// limiters over the same store stand for processes sharing it, the bucket never refills
+	store := kvmemory.NewMemoryKV[string, ratelimit.TokenBucketState]()
+	locks := kvmemory.NewLocks[string]()
+	opts := ratelimit.TokenBucketOptions{Burst: 50}
+	limiters := []ratelimit.Limiter[string]{
+		ratelimit.NewLocked(ratelimit.NewTokenBucket(store, opts), locks),
+		ratelimit.NewLocked(ratelimit.NewTokenBucket(store, opts), locks),
+	}
+
+	var allowed atomic.Int64
+	wg := sync.WaitGroup{}
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := limiters[i%len(limiters)].Allow(ctx, "a", 1)
+			require.NoError(err)
+			if ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.EqualValues(50, allowed.Load())
+}
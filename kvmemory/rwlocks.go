@@ -0,0 +1,164 @@
+package kvmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/royalcat/kv"
+)
+
+// NewRWLocks creates in-memory kv.RWLocks.
+// Waiting writers block new readers, so writers are not starved by a stream of readers.
+// State of a key is removed as soon as it has no holders and no waiters.
+func NewRWLocks[K kv.Bytes]() kv.RWLocks[K] {
+	return &rwLocks[K]{
+		locks: map[string]*rwLock{},
+	}
+}
+
+type rwLocks[K kv.Bytes] struct {
+	mu    sync.Mutex
+	locks map[string]*rwLock
+}
+
+type rwLock struct {
+	readers        int
+	writer         bool
+	waitingWriters int
+	// refs is the number of holders and waiters of the lock
+	refs int
+	// changed is closed and replaced when the lock is released
+	changed chan struct{}
+}
+
+var _ kv.RWLocks[string] = (*rwLocks[string])(nil)
+
+// Lock implements kv.Locks.
+func (l *rwLocks[K]) Lock(ctx context.Context, key K) error {
+	return l.acquire(ctx, string(key), true)
+}
+
+// TryLock implements kv.Locks.
+func (l *rwLocks[K]) TryLock(ctx context.Context, key K) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rw := l.locks[string(key)]
+	if rw != nil && (rw.writer || rw.readers > 0) {
+		return false, nil
+	}
+	rw = l.ref(string(key))
+	rw.writer = true
+	return true, nil
+}
+
+// Unlock implements kv.Locks.
+func (l *rwLocks[K]) Unlock(ctx context.Context, key K) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rw := l.locks[string(key)]
+	if rw == nil || !rw.writer {
+		return fmt.Errorf("lock is not locked for key: %v", key)
+	}
+	rw.writer = false
+	l.unref(string(key), rw)
+	return nil
+}
+
+// RLock implements kv.RWLocks.
+func (l *rwLocks[K]) RLock(ctx context.Context, key K) error {
+	return l.acquire(ctx, string(key), false)
+}
+
+// RUnlock implements kv.RWLocks.
+func (l *rwLocks[K]) RUnlock(ctx context.Context, key K) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rw := l.locks[string(key)]
+	if rw == nil || rw.readers == 0 {
+		return fmt.Errorf("lock is not read-locked for key: %v", key)
+	}
+	rw.readers--
+	l.unref(string(key), rw)
+	return nil
+}
+
+// Close implements kv.Locks.
+func (l *rwLocks[K]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for k, rw := range l.locks {
+		for ; rw.readers > 0; rw.readers-- {
+			l.unref(k, rw)
+		}
+		if rw.writer {
+			rw.writer = false
+			l.unref(k, rw)
+		}
+	}
+	return nil
+}
+
+func (l *rwLocks[K]) acquire(ctx context.Context, k string, write bool) error {
+	l.mu.Lock()
+	rw := l.ref(k)
+	if write {
+		rw.waitingWriters++
+	}
+
+	for {
+		if write && !rw.writer && rw.readers == 0 {
+			rw.waitingWriters--
+			rw.writer = true
+			l.mu.Unlock()
+			return nil
+		}
+		if !write && !rw.writer && rw.waitingWriters == 0 {
+			rw.readers++
+			l.mu.Unlock()
+			return nil
+		}
+
+		changed := rw.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+			l.mu.Lock()
+		case <-ctx.Done():
+			l.mu.Lock()
+			if write {
+				// readers may be waiting for this writer
+				rw.waitingWriters--
+			}
+			l.unref(k, rw)
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+}
+
+// ref returns the lock of the key adding a reference to it, it must be called with the mutex held.
+func (l *rwLocks[K]) ref(k string) *rwLock {
+	rw, ok := l.locks[k]
+	if !ok {
+		rw = &rwLock{changed: make(chan struct{})}
+		l.locks[k] = rw
+	}
+	rw.refs++
+	return rw
+}
+
+// unref removes a reference and wakes up the waiters, it must be called with the mutex held.
+func (l *rwLocks[K]) unref(k string, rw *rwLock) {
+	rw.refs--
+	close(rw.changed)
+	rw.changed = make(chan struct{})
+	if rw.refs <= 0 {
+		delete(l.locks, k)
+	}
+}
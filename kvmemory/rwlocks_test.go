@@ -0,0 +1,15 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestRWLocks(t *testing.T) {
+	testsuite.GoldenRWLocks(t, func() (kv.RWLocks[string], error) {
+		return kvmemory.NewRWLocks[string](), nil
+	})
+}
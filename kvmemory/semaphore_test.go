@@ -0,0 +1,46 @@
+package kvmemory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/semaphore"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemaphore(t *testing.T) {
+	testsuite.GoldenSemaphore(t,
+		func() (kv.Locks[string], error) {
+			return kvmemory.NewLocks[string](), nil
+		},
+		func() (kv.Store[string, semaphore.State], error) {
+			return kvmemory.NewMemoryKV[string, semaphore.State](), nil
+		},
+	)
+}
+
+var errUnlock = errors.New("lock expired")
+
+// failingUnlocks release locks but report a failed unlock, as if the lock expired meanwhile.
+type failingUnlocks struct {
+	kv.Locks[string]
+}
+
+func (l failingUnlocks) Unlock(ctx context.Context, key string) error {
+	return errors.Join(l.Locks.Unlock(ctx, key), errUnlock)
+}
+
+func TestSemaphoreUnlockError(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, err := semaphore.New(kvmemory.NewMemoryKV[string, semaphore.State](), failingUnlocks{kvmemory.NewLocks[string]()}, semaphore.DefaultOptions)
+	require.NoError(err)
+
+	_, err = s.Acquire(ctx, "key", 1)
+	require.ErrorIs(err, errUnlock)
+}
@@ -0,0 +1,17 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestStoreSequence(t *testing.T) {
+	store := kvmemory.NewMemoryKV[string, uint64]()
+
+	testsuite.GoldenSequence(t, func(key string) (kv.Sequence, error) {
+		return kv.NewStoreSequence(store, key, 10), nil
+	})
+}
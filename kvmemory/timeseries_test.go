@@ -0,0 +1,15 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/timeseries"
+)
+
+func TestTimeSeries(t *testing.T) {
+	testsuite.GoldenTimeSeries(t, func() (timeseries.Store, error) {
+		return kvmemory.NewOrderedKV[string, []byte](), nil
+	})
+}
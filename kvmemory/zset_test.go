@@ -0,0 +1,15 @@
+package kvmemory_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv/kvmemory"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+)
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		return kvmemory.NewOrderedKV[string, []byte](), nil
+	})
+}
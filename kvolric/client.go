@@ -0,0 +1,56 @@
+package kvolric
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/buraksezer/olric"
+	"github.com/royalcat/kv"
+)
+
+// NewClient creates a store connected to an Olric cluster with olric.NewClusterClient,
+// for processes which are not Olric nodes themselves. The store owns the client and closes it on Close.
+//
+// Locks shared through the cluster are created with [NewClientLocks], or with [NewLocks] over a DMap of an olric.ClusterClient.
+func NewClient[V any](addresses []string, bucket string, opts Options[V], clientOptions ...olric.ClusterClientOption) (kv.Store[string, V], error) {
+	c, err := olric.NewClusterClient(addresses, clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newStore(c, bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewClientLocks creates locks on top of the DMap of an Olric cluster connected with olric.NewClusterClient.
+// The locks own the client, Close releases held locks and closes it.
+func NewClientLocks(addresses []string, dmap string, defaultTimeout time.Duration, clientOptions ...olric.ClusterClientOption) (kv.Locks[string], error) {
+	c, err := olric.NewClusterClient(addresses, clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	dm, err := c.NewDMap(dmap)
+	if err != nil {
+		return nil, errors.Join(err, c.Close(context.Background()))
+	}
+
+	return &clientLocks{
+		Locks: NewLocks(dm, defaultTimeout),
+		c:     c,
+	}, nil
+}
+
+type clientLocks struct {
+	*Locks
+	c *olric.ClusterClient
+}
+
+// Close implements kv.Locks.
+func (l *clientLocks) Close(ctx context.Context) error {
+	return errors.Join(l.Locks.Close(ctx), l.c.Close(ctx))
+}
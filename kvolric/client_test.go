@@ -0,0 +1,56 @@
+package kvolric_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvolric"
+	"github.com/royalcat/kv/testsuite"
+)
+
+// newClientNode starts a node shut down at the end of the test and returns the address clients connect to.
+func newClientNode(t *testing.T) (string, error) {
+	db, addr, err := newNode()
+	if err != nil {
+		return "", err
+	}
+	t.Cleanup(func() {
+		if err := db.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	})
+	return addr, nil
+}
+
+func TestClientGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, func() (kv.Store[string, string], error) {
+		addr, err := newClientNode(t)
+		if err != nil {
+			return nil, err
+		}
+		opts := kvolric.DefaultOptions[string]()
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvolric.NewClient([]string{addr}, "test", opts)
+	})
+}
+
+func TestClientLocks(t *testing.T) {
+	testsuite.GoldenLocks(t, func() (kv.Locks[string], error) {
+		addr, err := newClientNode(t)
+		if err != nil {
+			return nil, err
+		}
+		locks, err := kvolric.NewClientLocks([]string{addr}, "locks", time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(func() {
+			if err := locks.Close(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+		return locks, nil
+	})
+}
@@ -0,0 +1,106 @@
+package kvolric
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/buraksezer/olric"
+	"github.com/royalcat/kv"
+)
+
+const counterLockTimeout = 10 * time.Second
+
+// NewCounter creates a kv.Counter in the DMap, values are stored in the shared kv.CounterValue encoding.
+//
+// Olric Incr works only with its own integer encoding and can't set an expiration, so an update holds an olric lock
+// of the counter and writes the value together with its expiration in a single Put.
+// Olric locks are stored as keys, so they are taken in the separate locks DMap, keeping them out of scans of counters.
+// When ttl is positive, it is applied when a counter is created, further increments keep the original expiration.
+func NewCounter(dm, locks olric.DMap, ttl time.Duration) *Counter {
+	return &Counter{
+		dm:    dm,
+		locks: locks,
+		ttl:   ttl,
+	}
+}
+
+type Counter struct {
+	dm    olric.DMap
+	locks olric.DMap
+	ttl   time.Duration
+}
+
+var _ kv.Counter[string] = (*Counter)(nil)
+
+// Incr implements kv.Counter.
+func (c *Counter) Incr(ctx context.Context, k string, delta int64) (n int64, err error) {
+	lc, err := c.locks.LockWithTimeout(ctx, k, counterLockTimeout, counterLockTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = errors.Join(err, lc.Unlock(context.WithoutCancel(ctx)))
+	}()
+
+	v, err := c.get(ctx, k)
+	if err != nil {
+		return 0, err
+	}
+	v = v.Incr(delta, c.ttl, time.Now())
+
+	data, err := v.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var opts []olric.PutOption
+	if !v.ExpiresAt.IsZero() {
+		opts = append(opts, olric.PXAT(time.Duration(v.ExpiresAt.UnixNano())))
+	}
+	err = c.dm.Put(ctx, k, data, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.Value, nil
+}
+
+// Decr implements kv.Counter.
+func (c *Counter) Decr(ctx context.Context, k string, delta int64) (int64, error) {
+	return c.Incr(ctx, k, -delta)
+}
+
+// GetCounter implements kv.Counter.
+func (c *Counter) GetCounter(ctx context.Context, k string) (int64, error) {
+	v, err := c.get(ctx, k)
+	if err != nil {
+		return 0, err
+	}
+	if v.Expired(time.Now()) {
+		return 0, nil
+	}
+
+	return v.Value, nil
+}
+
+// get returns the stored counter state, a missing counter is a zero state.
+func (c *Counter) get(ctx context.Context, k string) (kv.CounterValue, error) {
+	var v kv.CounterValue
+
+	resp, err := c.dm.Get(ctx, k)
+	if errors.Is(err, olric.ErrKeyNotFound) {
+		return v, nil
+	}
+	if err != nil {
+		return v, err
+	}
+
+	data, err := resp.Byte()
+	if err != nil {
+		return v, err
+	}
+
+	err = v.UnmarshalBinary(data)
+	return v, err
+}
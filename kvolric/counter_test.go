@@ -0,0 +1,60 @@
+package kvolric_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvolric"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	testsuite.GoldenCounter(t, func(ttl time.Duration) (kv.Counter[string], error) {
+		db, err := newDB()
+		if err != nil {
+			return nil, err
+		}
+
+		c := db.NewEmbeddedClient()
+		dm, err := c.NewDMap("counters")
+		if err != nil {
+			return nil, err
+		}
+		locks, err := c.NewDMap("counters_locks")
+		if err != nil {
+			return nil, err
+		}
+
+		return kvolric.NewCounter(dm, locks, ttl), nil
+	})
+}
+
+func TestCounterKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db, err := newDB()
+	require.NoError(err)
+	c := db.NewEmbeddedClient()
+	dm, err := c.NewDMap("counters")
+	require.NoError(err)
+	locks, err := c.NewDMap("counters_locks")
+	require.NoError(err)
+
+	counter := kvolric.NewCounter(dm, locks, 0)
+	_, err = counter.Incr(ctx, "a", 1)
+	require.NoError(err)
+
+	// locks of counters don't show up among them
+	it, err := dm.Scan(ctx)
+	require.NoError(err)
+	defer it.Close()
+	keys := []string{}
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	require.Equal([]string{"a"}, keys)
+}
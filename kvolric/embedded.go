@@ -3,7 +3,7 @@ package kvolric
 import (
 	"context"
 	"errors"
-	"log"
+	"regexp"
 	"time"
 
 	"github.com/buraksezer/olric"
@@ -16,48 +16,57 @@ func DefaultOptions[V any]() Options[V] {
 	}
 }
 
+// NewEmbedded creates a store inside an Olric node process using its embedded client.
 func NewEmbedded[V any](db *olric.Olric, bucket string, opts Options[V]) (kv.Store[string, V], error) {
-	e := db.NewEmbeddedClient()
-	dm, err := e.NewDMap(bucket)
+	s, err := newStore(db.NewEmbeddedClient(), bucket, opts)
 	if err != nil {
-		log.Fatalf("olric.NewDMap returned an error: %v", err)
+		return nil, err
+	}
+	return s, nil
+}
+
+// newStore creates a store on top of DMaps of the client, the store owns the client and closes it on Close,
+// the client is closed as well if the store can't be created.
+func newStore[V any](c olric.Client, bucket string, opts Options[V]) (*store[V], error) {
+	dm, err := c.NewDMap(bucket)
+	if err != nil {
+		return nil, errors.Join(err, c.Close(context.Background()))
 	}
 
-	locks, err := e.NewDMap(bucket + "_locks")
+	locks, err := c.NewDMap(bucket + "_locks")
 	if err != nil {
-		log.Fatalf("olric.NewDMap returned an error: %v", err)
+		return nil, errors.Join(err, c.Close(context.Background()))
 	}
 
-	return &embedded[V]{
-		c:       e,
+	return &store[V]{
+		c:       c,
 		dm:      dm,
 		locks:   locks,
 		Options: opts,
 	}, nil
-
 }
 
 type Options[V any] struct {
 	Codec kv.Codec[V]
 }
 
-type embedded[V any] struct {
+type store[V any] struct {
 	Options[V]
-	c     *olric.EmbeddedClient
+	c     olric.Client
 	dm    olric.DMap
 	locks olric.DMap
 }
 
-var _ kv.Store[string, struct{}] = (*embedded[struct{}])(nil)
+var _ kv.Store[string, struct{}] = (*store[struct{}])(nil)
 
 // Delete implements kv.Store.
-func (s *embedded[V]) Delete(ctx context.Context, k string) error {
+func (s *store[V]) Delete(ctx context.Context, k string) error {
 	_, err := s.dm.Delete(ctx, k)
 	return err
 }
 
 // Get implements kv.Store.
-func (s *embedded[V]) Get(ctx context.Context, k string) (V, error) {
+func (s *store[V]) Get(ctx context.Context, k string) (V, error) {
 	var v V
 	resp, err := s.dm.Get(ctx, k)
 	if err != nil {
@@ -79,7 +88,7 @@ func (s *embedded[V]) Get(ctx context.Context, k string) (V, error) {
 const editTimeout = 10 * time.Second
 
 // Get implements kv.Store.
-func (s *embedded[V]) Edit(ctx context.Context, k string, edit kv.Edit[V]) error {
+func (s *store[V]) Edit(ctx context.Context, k string, edit kv.Edit[V]) error {
 	lc, err := s.locks.LockWithTimeout(ctx, k, editTimeout, editTimeout)
 	if err != nil {
 		return err
@@ -115,7 +124,7 @@ func (s *embedded[V]) Edit(ctx context.Context, k string, edit kv.Edit[V]) error
 }
 
 // Range implements kv.Store.
-func (s *embedded[V]) Range(ctx context.Context, iter kv.Iter[string, V]) error {
+func (s *store[V]) Range(ctx context.Context, iter kv.Iter[string, V]) error {
 	it, err := s.dm.Scan(ctx)
 	if err != nil {
 		return err
@@ -142,8 +151,8 @@ func (s *embedded[V]) Range(ctx context.Context, iter kv.Iter[string, V]) error
 }
 
 // RangeWithPrefix implements kv.Store.
-func (s *embedded[V]) RangeWithPrefix(ctx context.Context, k string, iter kv.Iter[string, V]) error {
-	it, err := s.dm.Scan(ctx, olric.Match("^"+k))
+func (s *store[V]) RangeWithPrefix(ctx context.Context, k string, iter kv.Iter[string, V]) error {
+	it, err := s.dm.Scan(ctx, prefixMatch(k))
 	if err != nil {
 		return err
 	}
@@ -169,8 +178,73 @@ func (s *embedded[V]) RangeWithPrefix(ctx context.Context, k string, iter kv.Ite
 	return nil
 }
 
+var _ kv.StoreKeys[string] = (*store[struct{}])(nil)
+
+// RangeKeys implements kv.StoreKeys.
+func (s *store[V]) RangeKeys(ctx context.Context, iter kv.KeyIter[string]) error {
+	it, err := s.dm.Scan(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := iter(it.Key()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *store[V]) RangeKeysWithPrefix(ctx context.Context, k string, iter kv.KeyIter[string]) error {
+	it, err := s.dm.Scan(ctx, prefixMatch(k))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := iter(it.Key()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ kv.StoreHas[string] = (*store[struct{}])(nil)
+var _ kv.StoreCount[string] = (*store[struct{}])(nil)
+
+// Has implements kv.StoreHas.
+//
+// Olric has no existence check, so the value is fetched and discarded, which costs as much as Get without decoding.
+func (s *store[V]) Has(ctx context.Context, k string) (bool, error) {
+	_, err := s.dm.Get(ctx, k)
+	if errors.Is(err, olric.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Count implements kv.StoreCount.
+func (s *store[V]) Count(ctx context.Context, prefix string) (int, error) {
+	n := 0
+	err := s.RangeKeysWithPrefix(ctx, prefix, func(string) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+func prefixMatch(prefix string) olric.ScanOption {
+	return olric.Match("^" + regexp.QuoteMeta(prefix))
+}
+
 // Set implements kv.Store.
-func (s *embedded[V]) Set(ctx context.Context, k string, v V) error {
+func (s *store[V]) Set(ctx context.Context, k string, v V) error {
 	data, err := s.Codec.Marshal(v)
 	if err != nil {
 		return err
@@ -179,7 +253,22 @@ func (s *embedded[V]) Set(ctx context.Context, k string, v V) error {
 	return s.dm.Put(ctx, k, data)
 }
 
+var _ kv.StoreTTL[string, struct{}] = (*store[struct{}])(nil)
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *store[V]) SetWithTTL(ctx context.Context, k string, v V, ttl time.Duration) error {
+	data, err := s.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if ttl <= 0 {
+		return s.dm.Put(ctx, k, data)
+	}
+	return s.dm.Put(ctx, k, data, olric.PX(ttl))
+}
+
 // Close implements kv.Store.
-func (s *embedded[V]) Close(ctx context.Context) error {
+func (s *store[V]) Close(ctx context.Context) error {
 	return s.c.Close(ctx)
 }
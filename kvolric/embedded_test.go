@@ -2,7 +2,8 @@ package kvolric_test
 
 import (
 	"log"
-	"math/rand"
+	"net"
+	"strconv"
 	"testing"
 
 	"github.com/buraksezer/olric"
@@ -13,10 +14,25 @@ import (
 )
 
 func newDB() (*olric.Olric, error) {
+	db, _, err := newNode()
+	return db, err
+}
+
+// newNode starts a single-node cluster and returns it with the address clients connect to.
+func newNode() (*olric.Olric, string, error) {
 	c := config.New("local")
-	c.BindPort = 10000 + rand.Int()%10000
-	c.MemberlistConfig.BindPort = 10000 + rand.Int()%10000
-	// c.BindPort = 10000 + rand.Int()%40000
+	// 0.0.0.0 is resolved to the address of an interface, bind the loopback the clients connect to
+	c.BindAddr = "127.0.0.1"
+	c.MemberlistConfig.BindAddr = "127.0.0.1"
+	var err error
+	c.BindPort, err = freePort()
+	if err != nil {
+		return nil, "", err
+	}
+	c.MemberlistConfig.BindPort, err = freePort()
+	if err != nil {
+		return nil, "", err
+	}
 
 	// Callback function. It's called when this node is ready to accept connections.
 	started := make(chan struct{})
@@ -26,7 +42,7 @@ func newDB() (*olric.Olric, error) {
 
 	db, err := olric.New(c)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Start the instance. It will form a single-node cluster.
@@ -40,7 +56,17 @@ func newDB() (*olric.Olric, error) {
 
 	<-started
 
-	return db, nil
+	return db, net.JoinHostPort(c.BindAddr, strconv.Itoa(c.BindPort)), nil
+}
+
+// freePort returns a port which is free at the moment, as random ports collide between many test nodes.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
 }
 
 func newStore() (kv.Store[string, string], error) {
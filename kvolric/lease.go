@@ -0,0 +1,104 @@
+package kvolric
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/buraksezer/olric"
+	"github.com/royalcat/kv"
+)
+
+// LeaseLocks implements kv.LeaseLocks with olric locks, which are released by the cluster after the lease timeout.
+// Fencing tokens are kept in counters of the same DMap, next to the locked keys.
+type LeaseLocks struct {
+	defaultTimeout time.Duration
+
+	dm olric.DMap
+
+	mu     sync.Mutex
+	leases map[kv.Lease]struct{}
+}
+
+// NewLeaseLocks creates lease locks in the DMap,
+// defaultTimeout limits waiting for a lock when the context has no deadline.
+func NewLeaseLocks(dm olric.DMap, defaultTimeout time.Duration) *LeaseLocks {
+	return &LeaseLocks{
+		defaultTimeout: defaultTimeout,
+		dm:             dm,
+		leases:         map[kv.Lease]struct{}{},
+	}
+}
+
+var _ kv.LeaseLocks[string] = (*LeaseLocks)(nil)
+
+// Lock implements kv.LeaseLocks.
+func (l *LeaseLocks) Lock(ctx context.Context, key string, ttl time.Duration) (kv.Lease, error) {
+	if ttl <= 0 {
+		return nil, kv.ErrInvalidLeaseTTL
+	}
+
+	timeout := l.defaultTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	lc, err := l.dm.LockWithTimeout(ctx, key, ttl, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := l.dm.Incr(ctx, fenceKey(key), 1)
+	if err != nil {
+		return nil, errors.Join(err, lc.Unlock(ctx))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var lease kv.Lease
+	lease = kv.NewLease(uint64(token), ttl,
+		func(ctx context.Context) error {
+			return leaseError(lc.Lease(ctx, ttl))
+		},
+		func(ctx context.Context) error {
+			l.mu.Lock()
+			delete(l.leases, lease)
+			l.mu.Unlock()
+
+			return leaseError(lc.Unlock(ctx))
+		},
+	)
+	l.leases[lease] = struct{}{}
+	return lease, nil
+}
+
+// Close implements kv.LeaseLocks.
+func (l *LeaseLocks) Close(ctx context.Context) error {
+	l.mu.Lock()
+	leases := make([]kv.Lease, 0, len(l.leases))
+	for lease := range l.leases {
+		leases = append(leases, lease)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, lease := range leases {
+		err := lease.Release(ctx)
+		if err != nil && !errors.Is(err, kv.ErrLeaseLost) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func fenceKey(key string) string {
+	return "\x00fence\x00" + key
+}
+
+func leaseError(err error) error {
+	if errors.Is(err, olric.ErrNoSuchLock) {
+		return errors.Join(kv.ErrLeaseLost, err)
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package kvolric_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvolric"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestLeaseLocks(t *testing.T) {
+	testsuite.GoldenLeaseLocks(t, func() (kv.LeaseLocks[string], error) {
+		db, err := newDB()
+		if err != nil {
+			return nil, err
+		}
+
+		dm, err := db.NewEmbeddedClient().NewDMap("test")
+		if err != nil {
+			return nil, err
+		}
+
+		return kvolric.NewLeaseLocks(dm, time.Minute), nil
+	})
+}
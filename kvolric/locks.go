@@ -2,6 +2,7 @@ package kvolric
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ type Locks struct {
 	locks map[string]olric.LockContext
 }
 
+// NewLocks creates kv.Locks on top of the DMap, which may come from an embedded or a cluster client.
 func NewLocks(dm olric.DMap, defaultTimeout time.Duration) *Locks {
 	return &Locks{
 		defaultTimeout: defaultTimeout,
@@ -36,12 +38,37 @@ func (l *Locks) Lock(ctx context.Context, key string) error {
 		timeout = time.Until(deadline)
 	}
 	lc, err := l.dm.Lock(ctx, key, timeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			// olric reports a cancelled wait as not acquired lock
+			return errors.Join(ctx.Err(), err)
+		}
+		return err
+	}
+
+	l.mlock.Lock()
+	l.locks[key] = lc
+	l.mlock.Unlock()
+
+	return nil
+}
+
+// TryLock implements kv.Locks.
+func (l *Locks) TryLock(ctx context.Context, key string) (bool, error) {
+	// zero deadline makes a single attempt
+	lc, err := l.dm.Lock(ctx, key, 0)
+	if errors.Is(err, olric.ErrLockNotAcquired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
 
 	l.mlock.Lock()
 	l.locks[key] = lc
 	l.mlock.Unlock()
 
-	return err
+	return true, nil
 }
 
 // Unlock implements kv.Locks.
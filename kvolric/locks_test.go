@@ -25,3 +25,19 @@ func TestLocks(t *testing.T) {
 		return kvolric.NewLocks(dm, time.Minute), nil
 	})
 }
+
+func TestLockMany(t *testing.T) {
+	testsuite.GoldenLockMany(t, func() (kv.Locks[string], error) {
+		db, err := newDB()
+		if err != nil {
+			return nil, err
+		}
+
+		dm, err := db.NewEmbeddedClient().NewDMap("test")
+		if err != nil {
+			return nil, err
+		}
+
+		return kvolric.NewLocks(dm, time.Minute), nil
+	})
+}
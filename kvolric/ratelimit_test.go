@@ -0,0 +1,21 @@
+package kvolric_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvolric"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func newStateStore[V any]() (kv.Store[string, V], error) {
+	db, err := newDB()
+	if err != nil {
+		return nil, err
+	}
+	return kvolric.NewEmbedded(db, "ratelimit", kvolric.DefaultOptions[V]())
+}
+
+func TestRateLimitExpiry(t *testing.T) {
+	testsuite.GoldenRateLimitExpiry(t, newStateStore, newStateStore)
+}
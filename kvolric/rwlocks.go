@@ -0,0 +1,153 @@
+package kvolric
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/buraksezer/olric"
+	"github.com/royalcat/kv"
+)
+
+// RWLocks implements kv.RWLocks with an exclusive olric lock per key and a counter of readers.
+//
+// Readers hold the exclusive lock only while incrementing the counter, a writer holds it
+// for the whole write and waits until the counter drops to zero, so no new readers can enter meanwhile.
+// A reader which crashed without RUnlock leaves the counter incremented, blocking writers of the key
+// until their wait times out.
+type RWLocks struct {
+	*Locks
+
+	dm           olric.DMap
+	pollInterval time.Duration
+
+	mreaders sync.Mutex
+	// readers is the number of read locks held by this process per key
+	readers map[string]int
+}
+
+// NewRWLocks creates read/write locks in the DMap,
+// defaultTimeout limits waiting for a lock, including a writer waiting for readers, when the context has no deadline.
+func NewRWLocks(dm olric.DMap, defaultTimeout time.Duration) *RWLocks {
+	return &RWLocks{
+		Locks:        NewLocks(dm, defaultTimeout),
+		dm:           dm,
+		pollInterval: 10 * time.Millisecond,
+		readers:      map[string]int{},
+	}
+}
+
+var _ kv.RWLocks[string] = (*RWLocks)(nil)
+
+// Lock implements kv.Locks.
+//
+// The exclusive lock is released if the readers don't leave before the deadline of the context or defaultTimeout.
+func (l *RWLocks) Lock(ctx context.Context, key string) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.defaultTimeout)
+		defer cancel()
+	}
+
+	err := l.Locks.Lock(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	for {
+		n, err := l.readersCount(ctx, key)
+		if err != nil || n == 0 {
+			return l.unlockOnError(ctx, key, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return l.unlockOnError(ctx, key, ctx.Err())
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+// TryLock implements kv.Locks.
+func (l *RWLocks) TryLock(ctx context.Context, key string) (bool, error) {
+	ok, err := l.Locks.TryLock(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	n, err := l.readersCount(ctx, key)
+	if err != nil {
+		return false, l.unlockOnError(ctx, key, err)
+	}
+	if n > 0 {
+		return false, l.Locks.Unlock(ctx, key)
+	}
+	return true, nil
+}
+
+// RLock implements kv.RWLocks.
+func (l *RWLocks) RLock(ctx context.Context, key string) error {
+	err := l.Locks.Lock(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.dm.Incr(ctx, readersKey(key), 1)
+	if err == nil {
+		l.mreaders.Lock()
+		l.readers[key]++
+		l.mreaders.Unlock()
+	}
+	return errors.Join(err, l.Locks.Unlock(context.WithoutCancel(ctx), key))
+}
+
+// RUnlock implements kv.RWLocks.
+//
+// It returns kv.ErrLockNotHeld if this process holds no read lock of the key.
+func (l *RWLocks) RUnlock(ctx context.Context, key string) error {
+	l.mreaders.Lock()
+	if l.readers[key] == 0 {
+		l.mreaders.Unlock()
+		return kv.ErrLockNotHeld
+	}
+	l.readers[key]--
+	if l.readers[key] == 0 {
+		delete(l.readers, key)
+	}
+	l.mreaders.Unlock()
+
+	n, err := l.dm.Decr(ctx, readersKey(key), 1)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		// the counter was reset under a held read lock, it must not go below zero
+		_, err = l.dm.Incr(ctx, readersKey(key), -n)
+		return errors.Join(kv.ErrLockNotHeld, err)
+	}
+	return nil
+}
+
+func (l *RWLocks) readersCount(ctx context.Context, key string) (int, error) {
+	resp, err := l.dm.Get(ctx, readersKey(key))
+	if errors.Is(err, olric.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return resp.Int()
+}
+
+// unlockOnError releases the exclusive lock if err is not nil.
+func (l *RWLocks) unlockOnError(ctx context.Context, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.Join(err, l.Locks.Unlock(context.WithoutCancel(ctx), key))
+}
+
+func readersKey(key string) string {
+	return "\x00readers\x00" + key
+}
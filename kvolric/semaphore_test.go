@@ -0,0 +1,34 @@
+package kvolric_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvolric"
+	"github.com/royalcat/kv/semaphore"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestSemaphore(t *testing.T) {
+	db, err := newDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm, err := db.NewEmbeddedClient().NewDMap("locks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := 0
+	testsuite.GoldenSemaphore(t,
+		func() (kv.Locks[string], error) {
+			return kvolric.NewLocks(dm, time.Minute), nil
+		},
+		func() (kv.Store[string, semaphore.State], error) {
+			bucket++
+			return kvolric.NewEmbedded(db, fmt.Sprintf("semaphore-%d", bucket), kvolric.DefaultOptions[semaphore.State]())
+		},
+	)
+}
@@ -0,0 +1,20 @@
+package kvpebble
+
+import (
+	"github.com/cockroachdb/pebble"
+	"github.com/royalcat/kv"
+)
+
+type Options[V any] struct {
+	Codec         kv.Codec[V]
+	Dir           string
+	PebbleOptions *pebble.Options
+}
+
+func DefaultOptions[V any](dir string) Options[V] {
+	return Options[V]{
+		Codec:         kv.CodecJSON[V]{},
+		Dir:           dir,
+		PebbleOptions: &pebble.Options{},
+	}
+}
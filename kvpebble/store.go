@@ -0,0 +1,155 @@
+package kvpebble
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/royalcat/kv"
+)
+
+var errReadOnlyTransaction = errors.New("transaction is read-only")
+
+// New opens a pebble database in the options directory.
+// An empty directory with nil file system in pebble options opens an in-memory database.
+func New[K kv.Bytes, V any](opts Options[V]) (*Store[K, V], error) {
+	pebbleOpts := opts.PebbleOptions
+	if pebbleOpts == nil {
+		pebbleOpts = &pebble.Options{}
+	}
+	if opts.Dir == "" && pebbleOpts.FS == nil {
+		pebbleOpts = pebbleOpts.Clone()
+		pebbleOpts.FS = vfs.NewMem()
+	}
+
+	db, err := pebble.Open(opts.Dir, pebbleOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store[K, V]{
+		DB:      db,
+		Options: opts,
+	}, nil
+}
+
+// Store is a kv.Store on top of a pebble database.
+//
+// Pebble has no conflict detection, so writes through the store are serialized with an update transaction,
+// making Edit and transactions isolated from other writes through the same store.
+type Store[K kv.Bytes, V any] struct {
+	DB      *pebble.DB
+	Options Options[V]
+
+	// wmu serializes writes and update transactions
+	wmu sync.Mutex
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+var _ kv.StoreCount[string] = (*Store[string, string])(nil)
+var _ kv.TransactionalStore[string, string] = (*Store[string, string])(nil)
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	return s.DB.Close()
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return set(s.DB, []byte(k), v, s.Options.Codec)
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(s.DB, []byte(k), s.Options.Codec)
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return s.DB.Delete([]byte(k), pebble.Sync)
+}
+
+// Edit implements kv.Store.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	v, err := get(s.DB, []byte(k), s.Options.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return set(s.DB, []byte(k), v, s.Options.Codec)
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, nil, s.Options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, []byte(prefix), s.Options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *Store[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, s.DB, order, s.Options.Codec, iter)
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, nil, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, []byte(prefix), iter)
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	return has(s.DB, []byte(k))
+}
+
+// Count implements kv.StoreCount.
+func (s *Store[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	return count(ctx, s.DB, []byte(prefix))
+}
+
+// Transaction implements kv.TransactionalStore.
+//
+// An update transaction is an indexed batch committed atomically on Close, it holds the store write lock until then,
+// so the store itself must not be written while a transaction is open in the same goroutine.
+// A read-only transaction reads from a snapshot.
+func (s *Store[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	if !update {
+		return &transaction[K, V]{
+			reader:  s.DB.NewSnapshot(),
+			options: s.Options,
+		}, nil
+	}
+
+	s.wmu.Lock()
+	batch := s.DB.NewIndexedBatch()
+	return &transaction[K, V]{
+		reader:  batch,
+		batch:   batch,
+		unlock:  s.wmu.Unlock,
+		options: s.Options,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package kvpebble_test
+
+import (
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvpebble"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+)
+
+func newMemory[V any]() (kv.Store[string, V], error) {
+	return kvpebble.New[string, V](kvpebble.DefaultOptions[V](""))
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newMemory)
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	testsuite.FuzzPrefixBytes(t, newMemory)
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newMemory)
+}
+
+func TestPersistent(t *testing.T) {
+	testsuite.GoldenStrings(t, func() (kv.Store[string, string], error) {
+		opts := kvpebble.DefaultOptions[string](t.TempDir())
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvpebble.New[string, string](opts)
+	})
+}
+
+func newMemoryRaw() (*kvpebble.Store[string, []byte], error) {
+	opts := kvpebble.DefaultOptions[[]byte]("")
+	opts.Codec = kv.CodecBytes[[]byte]{}
+	return kvpebble.New[string, []byte](opts)
+}
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		return newMemoryRaw()
+	})
+}
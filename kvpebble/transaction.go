@@ -0,0 +1,90 @@
+package kvpebble
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/royalcat/kv"
+)
+
+type transaction[K kv.Bytes, V any] struct {
+	reader pebble.Reader
+	// batch is nil for read-only transactions
+	batch   *pebble.Batch
+	unlock  func()
+	options Options[V]
+	closed  bool
+}
+
+var _ kv.Store[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*transaction[string, string])(nil)
+
+// Close implements kv.Store, it commits an update transaction.
+func (t *transaction[K, V]) Close(ctx context.Context) error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if t.batch == nil {
+		return t.reader.Close()
+	}
+	defer t.unlock()
+
+	err := t.batch.Commit(pebble.Sync)
+	return errors.Join(err, t.batch.Close())
+}
+
+// Set implements kv.Store.
+func (t *transaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	if t.batch == nil {
+		return errReadOnlyTransaction
+	}
+	return set(t.batch, []byte(k), v, t.options.Codec)
+}
+
+// Get implements kv.Store.
+func (t *transaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(t.reader, []byte(k), t.options.Codec)
+}
+
+// Delete implements kv.Store.
+func (t *transaction[K, V]) Delete(ctx context.Context, k K) error {
+	if t.batch == nil {
+		return errReadOnlyTransaction
+	}
+	return t.batch.Delete([]byte(k), nil)
+}
+
+// Edit implements kv.Store.
+func (t *transaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	if t.batch == nil {
+		return errReadOnlyTransaction
+	}
+
+	v, err := get(t.reader, []byte(k), t.options.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return set(t.batch, []byte(k), v, t.options.Codec)
+}
+
+// Range implements kv.Store.
+func (t *transaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.reader, nil, t.options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *transaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.reader, []byte(prefix), t.options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *transaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, t.reader, order, t.options.Codec, iter)
+}
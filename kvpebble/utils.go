@@ -0,0 +1,129 @@
+package kvpebble
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/royalcat/kv"
+)
+
+func get[V any](r pebble.Reader, k []byte, codec kv.Codec[V]) (V, error) {
+	var v V
+	data, closer, err := r.Get(k)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return v, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	// the value is only valid until the closer is closed, and a codec may keep the slice
+	err = codec.Unmarshal(slices.Clone(data), &v)
+	return v, errors.Join(err, closer.Close())
+}
+
+func has(r pebble.Reader, k []byte) (bool, error) {
+	_, closer, err := r.Get(k)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, closer.Close()
+}
+
+func set[V any](w pebble.Writer, k []byte, v V, codec kv.Codec[V]) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.Set(k, data, pebble.Sync)
+}
+
+// rangeBounds iterates over keys in [lower, upper), nil bound means the range is unbounded on that side.
+// Keys and values passed to the iterator are copies, as the iterator reuses its buffers.
+func rangeBounds(ctx context.Context, r pebble.Reader, lower, upper []byte, reverse bool, iter func(k, v []byte) error) error {
+	it, err := r.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+
+	next, valid := it.Next, it.First()
+	if reverse {
+		next, valid = it.Prev, it.Last()
+	}
+	for ; valid; valid = next() {
+		err := ctx.Err()
+		if err == nil {
+			var v []byte
+			v, err = it.ValueAndErr()
+			if err == nil {
+				err = iter(slices.Clone(it.Key()), slices.Clone(v))
+			}
+		}
+		if err != nil {
+			// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+			it.Close()
+			return err
+		}
+	}
+	return errors.Join(it.Error(), it.Close())
+}
+
+func rangeValues[K kv.Bytes, V any](ctx context.Context, r pebble.Reader, lower, upper []byte, reverse bool, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeBounds(ctx, r, lower, upper, reverse, func(k, data []byte) error {
+		var v V
+		if err := codec.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		return iter(K(k), v)
+	})
+}
+
+func rangePrefix[K kv.Bytes, V any](ctx context.Context, r pebble.Reader, prefix []byte, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, r, prefix, prefixEnd(prefix), false, codec, iter)
+}
+
+func rangeOrdered[K kv.Bytes, V any](ctx context.Context, r pebble.Reader, order kv.Order[K], codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, r, nilIfEmpty(order.Min), nilIfEmpty(order.Max), order.Reverse, codec, iter)
+}
+
+func rangeKeys[K kv.Bytes](ctx context.Context, r pebble.Reader, prefix []byte, iter kv.KeyIter[K]) error {
+	return rangeBounds(ctx, r, prefix, prefixEnd(prefix), false, func(k, _ []byte) error {
+		return iter(K(k))
+	})
+}
+
+func count(ctx context.Context, r pebble.Reader, prefix []byte) (int, error) {
+	n := 0
+	err := rangeBounds(ctx, r, prefix, prefixEnd(prefix), false, func(_, _ []byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// prefixEnd returns the smallest key greater than all keys with the prefix, nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func nilIfEmpty[K kv.Bytes](k K) []byte {
+	if len(k) == 0 {
+		return nil
+	}
+	return []byte(k)
+}
@@ -0,0 +1,155 @@
+package kvredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/royalcat/kv"
+)
+
+const lockPollInterval = 10 * time.Millisecond
+
+// unlockScript deletes the lock only if it is still held by the given token.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DefaultLockKeyPrefix is the prefix of lock keys of NewLocks unless WithLockKeyPrefix is given.
+const DefaultLockKeyPrefix = "lock:"
+
+// LocksOption configures locks created by NewLocks.
+type LocksOption func(*locksOptions)
+
+type locksOptions struct {
+	keyPrefix string
+}
+
+// WithLockKeyPrefix sets the prefix of lock keys.
+func WithLockKeyPrefix(prefix string) LocksOption {
+	return func(o *locksOptions) {
+		o.keyPrefix = prefix
+	}
+}
+
+// NewLocks creates kv.Locks acquired with SET NX PX on a Redis protocol server,
+// so they are shared by all clients of the server.
+//
+// The lock of a key is stored under the key with a prefix, DefaultLockKeyPrefix by default,
+// so locks may guard keys holding data. Lock keys share the keyspace with other keys,
+// so the prefix must not be a prefix of data keys, which are better ranged with their own prefix.
+//
+// A lock expires after ttl and then can be reclaimed by another owner, so ttl must exceed the longest time a lock is held.
+// Every acquisition gets its own token and the lock is released only while it still holds the token,
+// so an expired lock can't be released by its previous holder.
+func NewLocks[K kv.Bytes](client redis.UniversalClient, ttl time.Duration, opts ...LocksOption) *Locks[K] {
+	o := locksOptions{keyPrefix: DefaultLockKeyPrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Locks[K]{
+		client:    client,
+		ttl:       ttl,
+		keyPrefix: o.keyPrefix,
+		id:        kv.NewLockOwner(),
+		held:      map[string]string{},
+	}
+}
+
+type Locks[K kv.Bytes] struct {
+	client    redis.UniversalClient
+	ttl       time.Duration
+	keyPrefix string
+
+	id  string
+	seq atomic.Uint64
+
+	mu   sync.Mutex
+	held map[string]string
+}
+
+var _ kv.Locks[string] = (*Locks[string])(nil)
+
+// Lock implements kv.Locks.
+func (l *Locks[K]) Lock(ctx context.Context, key K) error {
+	for {
+		ok, err := l.TryLock(ctx, key)
+		if err != nil || ok {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// TryLock implements kv.Locks.
+func (l *Locks[K]) TryLock(ctx context.Context, key K) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	token := fmt.Sprintf("%s-%d", l.id, l.seq.Add(1))
+	ok, err := l.client.SetNX(ctx, l.keyPrefix+string(key), token, l.ttl).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.held[string(key)] = token
+	l.mu.Unlock()
+	return true, nil
+}
+
+// Unlock implements kv.Locks.
+// It returns kv.ErrLockNotHeld if the lock expired and was reclaimed by another owner.
+func (l *Locks[K]) Unlock(ctx context.Context, key K) error {
+	l.mu.Lock()
+	token, ok := l.held[string(key)]
+	delete(l.held, string(key))
+	l.mu.Unlock()
+	if !ok {
+		return kv.ErrLockNotHeld
+	}
+
+	return l.release(ctx, string(key), token)
+}
+
+// Close implements kv.Locks.
+func (l *Locks[K]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	held := l.held
+	l.held = map[string]string{}
+	l.mu.Unlock()
+
+	var errs []error
+	for k, token := range held {
+		err := l.release(ctx, k, token)
+		if err != nil && !errors.Is(err, kv.ErrLockNotHeld) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Locks[K]) release(ctx context.Context, key, token string) error {
+	n, err := unlockScript.Run(ctx, l.client, []string{l.keyPrefix + key}, token).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return kv.ErrLockNotHeld
+	}
+	return nil
+}
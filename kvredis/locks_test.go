@@ -0,0 +1,38 @@
+package kvredis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvredis"
+	"github.com/royalcat/kv/testsuite"
+)
+
+func TestLocks(t *testing.T) {
+	testsuite.GoldenLocks(t, func() (kv.Locks[string], error) {
+		client, _ := newClient(t)
+		return kvredis.NewLocks[string](client, time.Minute), nil
+	})
+}
+
+func TestStaleLocks(t *testing.T) {
+	testsuite.GoldenStaleLocks(t, func(ttl time.Duration) (kv.Locks[string], kv.Locks[string], error) {
+		client, _ := newClient(t)
+		return kvredis.NewLocks[string](client, ttl), kvredis.NewLocks[string](client, ttl), nil
+	})
+}
+
+func TestLockMany(t *testing.T) {
+	testsuite.GoldenLockMany(t, func() (kv.Locks[string], error) {
+		client, _ := newClient(t)
+		return kvredis.NewLocks[string](client, time.Minute, kvredis.WithLockKeyPrefix("locks/")), nil
+	})
+}
+
+func TestLocksWithData(t *testing.T) {
+	testsuite.GoldenLocksWithData(t, func() (kv.Store[string, string], kv.Locks[string], error) {
+		client, _ := newClient(t)
+		return kvredis.New[string, string](client, kvredis.DefaultOptions[string]()), kvredis.NewLocks[string](client, time.Minute), nil
+	})
+}
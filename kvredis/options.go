@@ -0,0 +1,22 @@
+package kvredis
+
+import (
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+type Options[V any] struct {
+	Codec kv.Codec[V]
+	// DefaultTTL is applied to every written value when positive.
+	DefaultTTL time.Duration
+	// ScanCount is a hint of the number of keys returned by a single SCAN call.
+	ScanCount int64
+}
+
+func DefaultOptions[V any]() Options[V] {
+	return Options[V]{
+		Codec:     kv.CodecJSON[V]{},
+		ScanCount: 100,
+	}
+}
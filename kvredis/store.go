@@ -0,0 +1,281 @@
+package kvredis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/royalcat/kv"
+)
+
+// New creates a store on top of a Redis protocol server, such as Redis, Valkey or KeyDB.
+// The client is owned by the caller, closing the store doesn't close it.
+func New[K kv.Bytes, V any](client redis.UniversalClient, opts Options[V]) *Store[K, V] {
+	return &Store[K, V]{
+		Client:  client,
+		Options: opts,
+	}
+}
+
+// Store is a kv.Store keeping every key as a Redis string.
+//
+// Ranges are read with SCAN, so keys written or deleted during a range may or may not be visited.
+// With a cluster client every master node is scanned.
+type Store[K kv.Bytes, V any] struct {
+	Client  redis.UniversalClient
+	Options Options[V]
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+var _ kv.StoreCount[string] = (*Store[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*Store[string, string])(nil)
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	return nil
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	data, err := s.Options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Client.Set(ctx, string(k), data, s.Options.DefaultTTL).Err()
+}
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *Store[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	data, err := s.Options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Client.Set(ctx, string(k), data, max(ttl, 0)).Err()
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (V, error) {
+	var v V
+	data, err := s.Client.Get(ctx, string(k)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return v, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	err = s.Options.Codec.Unmarshal(data, &v)
+	return v, err
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	return s.Client.Del(ctx, string(k)).Err()
+}
+
+// Edit implements kv.Store.
+//
+// The key is watched while the value is edited and written in MULTI/EXEC,
+// if the key is modified concurrently, the transaction fails and the edit is retried.
+// Without DefaultTTL the expiration of the key is kept.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	key := string(k)
+	for {
+		err := s.Client.Watch(ctx, func(tx *redis.Tx) error {
+			var v V
+			data, err := tx.Get(ctx, key).Bytes()
+			if errors.Is(err, redis.Nil) {
+				return kv.ErrKeyNotFound
+			}
+			if err != nil {
+				return err
+			}
+			err = s.Options.Codec.Unmarshal(data, &v)
+			if err != nil {
+				return err
+			}
+
+			v, err = edit(ctx, v)
+			if err != nil {
+				return err
+			}
+			data, err = s.Options.Codec.Marshal(v)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+				if s.Options.DefaultTTL > 0 {
+					p.Set(ctx, key, data, s.Options.DefaultTTL)
+				} else {
+					p.SetArgs(ctx, key, data, redis.SetArgs{KeepTTL: true})
+				}
+				return nil
+			})
+			return err
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		return err
+	}
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return s.RangeWithPrefix(ctx, K(""), iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return s.scan(ctx, string(prefix), func(keys []string) error {
+		cmds, err := s.Client.Pipelined(ctx, func(p redis.Pipeliner) error {
+			for _, k := range keys {
+				p.Get(ctx, k)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		for i, cmd := range cmds {
+			data, err := cmd.(*redis.StringCmd).Bytes()
+			if errors.Is(err, redis.Nil) {
+				// deleted while scanning
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			var v V
+			if err := s.Options.Codec.Unmarshal(data, &v); err != nil {
+				return err
+			}
+			// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+			if err := iter(K(keys[i]), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return s.RangeKeysWithPrefix(ctx, K(""), iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return s.scan(ctx, string(prefix), func(keys []string) error {
+		for _, k := range keys {
+			if err := iter(K(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	n, err := s.Client.Exists(ctx, string(k)).Result()
+	return n > 0, err
+}
+
+// Count implements kv.StoreCount.
+func (s *Store[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	n := 0
+	err := s.scan(ctx, string(prefix), func(keys []string) error {
+		n += len(keys)
+		return nil
+	})
+	return n, err
+}
+
+// scan calls fn with pages of keys with the prefix, every key is passed once.
+func (s *Store[K, V]) scan(ctx context.Context, prefix string, fn func(keys []string) error) error {
+	nodes, err := s.nodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	match := matchPrefix(prefix)
+	// SCAN may return a key more than once
+	seen := map[string]struct{}{}
+	for _, node := range nodes {
+		var cursor uint64
+		for {
+			var keys []string
+			keys, cursor, err = node.Scan(ctx, cursor, match, s.Options.ScanCount).Result()
+			if err != nil {
+				return err
+			}
+
+			page := keys[:0]
+			for _, k := range keys {
+				if _, ok := seen[k]; ok || !strings.HasPrefix(k, prefix) {
+					continue
+				}
+				seen[k] = struct{}{}
+				page = append(page, k)
+			}
+			if len(page) > 0 {
+				if err := fn(page); err != nil {
+					return err
+				}
+			}
+
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// nodes returns clients to scan, a cluster is scanned on every master.
+func (s *Store[K, V]) nodes(ctx context.Context) ([]redis.Cmdable, error) {
+	cluster, ok := s.Client.(*redis.ClusterClient)
+	if !ok {
+		return []redis.Cmdable{s.Client}, nil
+	}
+
+	var mu sync.Mutex
+	var nodes []redis.Cmdable
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, c *redis.Client) error {
+		mu.Lock()
+		nodes = append(nodes, c)
+		mu.Unlock()
+		return nil
+	})
+	return nodes, err
+}
+
+// matchPrefix returns a SCAN MATCH pattern of keys with the prefix.
+// The pattern covers the prefix only up to the first non-ASCII byte,
+// as some implementations match patterns as UTF-8 text, so the keys are filtered by the caller too.
+func matchPrefix(prefix string) string {
+	var b strings.Builder
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if c >= 0x80 {
+			break
+		}
+		if strings.IndexByte(`*?[]\`, c) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('*')
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package kvredis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvredis"
+	"github.com/royalcat/kv/ratelimit"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/stretchr/testify/require"
+)
+
+// newClient starts an in-process Redis stand-in and returns a client connected to it,
+// both are closed at the end of the test.
+func newClient(t testing.TB) (*redis.Client, *miniredis.Miniredis) {
+	m, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(m.Close)
+
+	// miniredis doesn't expire keys on its own, its clock is advanced along with the real one
+	const tick = 10 * time.Millisecond
+	ticker := time.NewTicker(tick)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				m.FastForward(tick)
+			case <-done:
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		ticker.Stop()
+		close(done)
+	})
+
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, m
+}
+
+// newStore returns a constructor of stores, each on its own Redis stand-in.
+func newStore[V any](t testing.TB) testsuite.StoreConstructor[string, V] {
+	return func() (kv.Store[string, V], error) {
+		client, _ := newClient(t)
+		return kvredis.New[string, V](client, kvredis.DefaultOptions[V]()), nil
+	}
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newStore[string](t))
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	// the fuzz target can't register cleanups, so a single stand-in is emptied for every store
+	client, m := newClient(t)
+
+	// values are stored as is, as JSON replaces invalid UTF-8 of fuzzed values
+	testsuite.FuzzPrefixBytes(t, func() (kv.Store[string, string], error) {
+		m.FlushAll()
+		opts := kvredis.DefaultOptions[string]()
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvredis.New[string, string](client, opts), nil
+	})
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newStore[testsuite.TestObject](t))
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	client, m := newClient(t)
+
+	opts := kvredis.DefaultOptions[string]()
+	opts.Codec = kv.CodecBytes[string]{}
+	s := kvredis.New[string, string](client, opts)
+
+	require.NoError(client.Set(ctx, "a", "1", time.Minute).Err())
+	require.NoError(s.Edit(ctx, "a", func(ctx context.Context, v string) (string, error) {
+		return v + "2", nil
+	}))
+	require.Equal(time.Minute, m.TTL("a").Round(time.Minute), "edit keeps the expiration")
+
+	opts.DefaultTTL = time.Second
+	s = kvredis.New[string, string](client, opts)
+	require.NoError(s.Set(ctx, "b", "1"))
+	m.FastForward(2 * time.Second)
+
+	_, err := s.Get(ctx, "b")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+}
+
+func TestEditConflict(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	store, err := newStore[int](t)()
+	require.NoError(err)
+	require.NoError(store.Set(ctx, "n", 0))
+
+	const workers = 10
+	done := make(chan error, workers)
+	for range workers {
+		go func() {
+			done <- store.Edit(ctx, "n", func(ctx context.Context, v int) (int, error) {
+				return v + 1, nil
+			})
+		}()
+	}
+	for range workers {
+		require.NoError(<-done)
+	}
+
+	n, err := store.Get(ctx, "n")
+	require.NoError(err)
+	require.Equal(workers, n)
+}
+
+func TestRateLimitExpiry(t *testing.T) {
+	testsuite.GoldenRateLimitExpiry(t, newStore[ratelimit.TokenBucketState](t), newStore[ratelimit.SlidingWindowState](t))
+}
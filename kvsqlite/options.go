@@ -0,0 +1,28 @@
+package kvsqlite
+
+import (
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+type Options[V any] struct {
+	Codec kv.Codec[V]
+	// Path is the database file path, empty path opens an in-memory database.
+	Path string
+	// Table is the name of the table holding the key-value pairs, it is created if not exists.
+	Table string
+	// DefaultTTL is applied to every written value when positive.
+	DefaultTTL time.Duration
+	// CleanupInterval is the interval of expired rows removal, zero disables the background cleanup.
+	CleanupInterval time.Duration
+}
+
+func DefaultOptions[V any](path string) Options[V] {
+	return Options[V]{
+		Codec:           kv.CodecJSON[V]{},
+		Path:            path,
+		Table:           "kv",
+		CleanupInterval: time.Minute,
+	}
+}
@@ -0,0 +1,256 @@
+package kvsqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/royalcat/kv"
+	_ "modernc.org/sqlite"
+)
+
+var errReadOnlyTransaction = errors.New("transaction is read-only")
+
+// memoryID makes in-memory database names unique within the process.
+var memoryID atomic.Uint64
+
+// New opens a sqlite database at the options path and creates the table if not exists.
+// An empty path opens an in-memory database, which lives until the store is closed.
+//
+// The database is opened with the cgo-free modernc.org/sqlite driver,
+// the table can be inspected with the sqlite CLI as (key BLOB PRIMARY KEY, value BLOB, expires_at INTEGER),
+// where expires_at is a unix time in nanoseconds or NULL for values without expiration.
+func New[K kv.Bytes, V any](opts Options[V]) (*Store[K, V], error) {
+	if opts.Table == "" {
+		opts.Table = "kv"
+	}
+
+	db, err := sql.Open("sqlite", dsn(opts.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	err = createTable(context.Background(), db, opts.Table)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &Store[K, V]{
+		DB:      db,
+		Options: opts,
+	}
+
+	if opts.CleanupInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopCleanup = cancel
+		s.cleanupDone = make(chan struct{})
+		go s.cleanup(ctx, opts.CleanupInterval)
+	}
+
+	return s, nil
+}
+
+// dsn builds a data source name with write transactions beginning immediately,
+// so concurrent writers wait for each other instead of failing on a lock upgrade.
+func dsn(path string) string {
+	query := url.Values{}
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Set("_txlock", "immediate")
+
+	if path == "" {
+		// memdb databases with a name starting with a slash are shared between connections of the process
+		query.Set("vfs", "memdb")
+		return fmt.Sprintf("file:/kvsqlite-%d?%s", memoryID.Add(1), query.Encode())
+	}
+
+	query.Add("_pragma", "journal_mode(WAL)")
+	u := url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// Store is a kv.Store on top of a sqlite table.
+//
+// Expired rows are invisible for reads and removed by the background cleanup or [Store.DeleteExpired].
+// Writes through the store are serialized with update transactions,
+// making Edit and transactions isolated from other writes through the same store.
+type Store[K kv.Bytes, V any] struct {
+	DB      *sql.DB
+	Options Options[V]
+
+	// wmu serializes writes and update transactions
+	wmu sync.Mutex
+
+	stopCleanup context.CancelFunc
+	cleanupDone chan struct{}
+}
+
+var _ kv.Store[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*Store[string, string])(nil)
+var _ kv.StoreKeys[string] = (*Store[string, string])(nil)
+var _ kv.StoreHas[string] = (*Store[string, string])(nil)
+var _ kv.StoreCount[string] = (*Store[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*Store[string, string])(nil)
+var _ kv.TransactionalStore[string, string] = (*Store[string, string])(nil)
+
+func (s *Store[K, V]) cleanup(ctx context.Context, interval time.Duration) {
+	defer close(s.cleanupDone)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			// errors are retried on the next tick, expired rows are invisible until removed anyway
+			_, _ = s.DeleteExpired(ctx)
+		}
+	}
+}
+
+// DeleteExpired removes expired rows from the table and returns the number of removed rows.
+func (s *Store[K, V]) DeleteExpired(ctx context.Context) (int, error) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return deleteExpired(ctx, s.DB, s.Options.Table)
+}
+
+// Close implements kv.Store.
+func (s *Store[K, V]) Close(ctx context.Context) error {
+	if s.stopCleanup != nil {
+		s.stopCleanup()
+		<-s.cleanupDone
+	}
+	return s.DB.Close()
+}
+
+// Set implements kv.Store.
+func (s *Store[K, V]) Set(ctx context.Context, k K, v V) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return set(ctx, s.DB, s.Options.Table, []byte(k), v, s.Options.Codec, s.Options.DefaultTTL)
+}
+
+// SetWithTTL implements kv.StoreTTL.
+func (s *Store[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return set(ctx, s.DB, s.Options.Table, []byte(k), v, s.Options.Codec, ttl)
+}
+
+// Get implements kv.Store.
+func (s *Store[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(ctx, s.DB, s.Options.Table, []byte(k), s.Options.Codec)
+}
+
+// Delete implements kv.Store.
+func (s *Store[K, V]) Delete(ctx context.Context, k K) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return del(ctx, s.DB, s.Options.Table, []byte(k))
+}
+
+// Edit implements kv.Store.
+func (s *Store[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = editTx(ctx, tx, s.Options, []byte(k), edit)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+func editTx[V any](ctx context.Context, q querier, opts Options[V], k []byte, edit kv.Edit[V]) error {
+	v, err := get(ctx, q, opts.Table, k, opts.Codec)
+	if err != nil {
+		return err
+	}
+	v, err = edit(ctx, v)
+	if err != nil {
+		return err
+	}
+	return set(ctx, q, opts.Table, k, v, opts.Codec, opts.DefaultTTL)
+}
+
+// Range implements kv.Store.
+func (s *Store[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, s.Options.Table, nil, s.Options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (s *Store[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, s.DB, s.Options.Table, []byte(prefix), s.Options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (s *Store[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, s.DB, s.Options.Table, order, s.Options.Codec, iter)
+}
+
+// RangeKeys implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeys(ctx context.Context, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, s.Options.Table, nil, iter)
+}
+
+// RangeKeysWithPrefix implements kv.StoreKeys.
+func (s *Store[K, V]) RangeKeysWithPrefix(ctx context.Context, prefix K, iter kv.KeyIter[K]) error {
+	return rangeKeys(ctx, s.DB, s.Options.Table, []byte(prefix), iter)
+}
+
+// Has implements kv.StoreHas.
+func (s *Store[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	return has(ctx, s.DB, s.Options.Table, []byte(k))
+}
+
+// Count implements kv.StoreCount.
+func (s *Store[K, V]) Count(ctx context.Context, prefix K) (int, error) {
+	return count(ctx, s.DB, s.Options.Table, []byte(prefix))
+}
+
+// Transaction implements kv.TransactionalStore.
+//
+// An update transaction holds the store write lock until Close,
+// so the store itself must not be written while a transaction is open in the same goroutine.
+// An in-memory database has no write-ahead log, so a long read-only transaction delays commits of writers.
+func (s *Store[K, V]) Transaction(update bool) (kv.Store[K, V], error) {
+	if !update {
+		tx, err := s.DB.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		return &transaction[K, V]{
+			tx:      tx,
+			options: s.Options,
+		}, nil
+	}
+
+	s.wmu.Lock()
+	tx, err := s.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		s.wmu.Unlock()
+		return nil, err
+	}
+	return &transaction[K, V]{
+		tx:      tx,
+		update:  true,
+		unlock:  s.wmu.Unlock,
+		options: s.Options,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package kvsqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/kvsqlite"
+	"github.com/royalcat/kv/queue"
+	"github.com/royalcat/kv/testsuite"
+	"github.com/royalcat/kv/zset"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemory[V any]() (kv.Store[string, V], error) {
+	return kvsqlite.New[string, V](kvsqlite.DefaultOptions[V](""))
+}
+
+func TestGolden(t *testing.T) {
+	testsuite.GoldenStrings(t, newMemory)
+}
+
+func FuzzPrefixBytes(t *testing.F) {
+	testsuite.FuzzPrefixBytes(t, newMemory)
+}
+
+func TestGoldenObjects(t *testing.T) {
+	testsuite.GoldenObjects(t, newMemory)
+}
+
+func TestPersistent(t *testing.T) {
+	testsuite.GoldenStrings(t, func() (kv.Store[string, string], error) {
+		opts := kvsqlite.DefaultOptions[string](filepath.Join(t.TempDir(), "kv.db"))
+		opts.Codec = kv.CodecBytes[string]{}
+		return kvsqlite.New[string, string](opts)
+	})
+}
+
+func newMemoryRaw() (*kvsqlite.Store[string, []byte], error) {
+	opts := kvsqlite.DefaultOptions[[]byte]("")
+	opts.Codec = kv.CodecBytes[[]byte]{}
+	return kvsqlite.New[string, []byte](opts)
+}
+
+func TestQueue(t *testing.T) {
+	testsuite.GoldenQueue(t, func() (queue.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestSortedSet(t *testing.T) {
+	testsuite.GoldenSortedSet(t, func() (zset.Store, error) {
+		return newMemoryRaw()
+	})
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	opts := kvsqlite.DefaultOptions[string]("")
+	opts.DefaultTTL = 50 * time.Millisecond
+	opts.CleanupInterval = 0
+	s, err := kvsqlite.New[string, string](opts)
+	require.NoError(err)
+	defer s.Close(ctx)
+
+	require.NoError(s.Set(ctx, "a", "1"))
+	v, err := s.Get(ctx, "a")
+	require.NoError(err)
+	require.Equal("1", v)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = s.Get(ctx, "a")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+	n, err := s.Count(ctx, "")
+	require.NoError(err)
+	require.Zero(n)
+
+	removed, err := s.DeleteExpired(ctx)
+	require.NoError(err)
+	require.Equal(1, removed)
+}
+
+func TestRateLimitExpiry(t *testing.T) {
+	testsuite.GoldenRateLimitExpiry(t, newMemory, newMemory)
+}
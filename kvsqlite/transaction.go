@@ -0,0 +1,86 @@
+package kvsqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+type transaction[K kv.Bytes, V any] struct {
+	tx      *sql.Tx
+	update  bool
+	unlock  func()
+	options Options[V]
+	closed  bool
+}
+
+var _ kv.Store[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreOrdered[string, string] = (*transaction[string, string])(nil)
+var _ kv.StoreTTL[string, string] = (*transaction[string, string])(nil)
+
+// Close implements kv.Store, it commits the transaction.
+func (t *transaction[K, V]) Close(ctx context.Context) error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if t.unlock != nil {
+		defer t.unlock()
+	}
+	return t.tx.Commit()
+}
+
+// Set implements kv.Store.
+func (t *transaction[K, V]) Set(ctx context.Context, k K, v V) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return set(ctx, t.tx, t.options.Table, []byte(k), v, t.options.Codec, t.options.DefaultTTL)
+}
+
+// SetWithTTL implements kv.StoreTTL.
+func (t *transaction[K, V]) SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return set(ctx, t.tx, t.options.Table, []byte(k), v, t.options.Codec, ttl)
+}
+
+// Get implements kv.Store.
+func (t *transaction[K, V]) Get(ctx context.Context, k K) (V, error) {
+	return get(ctx, t.tx, t.options.Table, []byte(k), t.options.Codec)
+}
+
+// Delete implements kv.Store.
+func (t *transaction[K, V]) Delete(ctx context.Context, k K) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return del(ctx, t.tx, t.options.Table, []byte(k))
+}
+
+// Edit implements kv.Store.
+func (t *transaction[K, V]) Edit(ctx context.Context, k K, edit kv.Edit[V]) error {
+	if !t.update {
+		return errReadOnlyTransaction
+	}
+	return editTx(ctx, t.tx, t.options, []byte(k), edit)
+}
+
+// Range implements kv.Store.
+func (t *transaction[K, V]) Range(ctx context.Context, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.tx, t.options.Table, nil, t.options.Codec, iter)
+}
+
+// RangeWithPrefix implements kv.Store.
+func (t *transaction[K, V]) RangeWithPrefix(ctx context.Context, prefix K, iter kv.Iter[K, V]) error {
+	return rangePrefix(ctx, t.tx, t.options.Table, []byte(prefix), t.options.Codec, iter)
+}
+
+// RangeOrdered implements kv.StoreOrdered.
+func (t *transaction[K, V]) RangeOrdered(ctx context.Context, order kv.Order[K], iter kv.Iter[K, V]) error {
+	return rangeOrdered(ctx, t.tx, t.options.Table, order, t.options.Codec, iter)
+}
@@ -0,0 +1,259 @@
+package kvsqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+// pageSize is the number of rows read by a single query while ranging,
+// rows are read in pages so the iterator is free to write to the store.
+const pageSize = 256
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func createTable(ctx context.Context, q querier, table string) error {
+	_, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+quoteIdent(table)+` (
+	key BLOB PRIMARY KEY,
+	value BLOB NOT NULL,
+	expires_at INTEGER
+) WITHOUT ROWID`)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS `+quoteIdent(table+"_expires_at")+
+		` ON `+quoteIdent(table)+` (expires_at) WHERE expires_at IS NOT NULL`)
+	return err
+}
+
+func get[V any](ctx context.Context, q querier, table string, k []byte, codec kv.Codec[V]) (V, error) {
+	var v V
+	var data []byte
+	err := q.QueryRowContext(ctx,
+		`SELECT value FROM `+quoteIdent(table)+` WHERE key = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		blob(k), time.Now().UnixNano(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return v, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return v, err
+	}
+	err = codec.Unmarshal(data, &v)
+	return v, err
+}
+
+func has(ctx context.Context, q querier, table string, k []byte) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM `+quoteIdent(table)+` WHERE key = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		blob(k), time.Now().UnixNano(),
+	).Scan(&n)
+	return n > 0, err
+}
+
+func set[V any](ctx context.Context, q querier, table string, k []byte, v V, codec kv.Codec[V], ttl time.Duration) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt *int64
+	if ttl > 0 {
+		exp := time.Now().Add(ttl).UnixNano()
+		expiresAt = &exp
+	}
+
+	_, err = q.ExecContext(ctx,
+		`INSERT OR REPLACE INTO `+quoteIdent(table)+` (key, value, expires_at) VALUES (?, ?, ?)`,
+		blob(k), blob(data), expiresAt,
+	)
+	return err
+}
+
+func del(ctx context.Context, q querier, table string, k []byte) error {
+	_, err := q.ExecContext(ctx, `DELETE FROM `+quoteIdent(table)+` WHERE key = ?`, blob(k))
+	return err
+}
+
+func deleteExpired(ctx context.Context, q querier, table string) (int, error) {
+	res, err := q.ExecContext(ctx,
+		`DELETE FROM `+quoteIdent(table)+` WHERE expires_at IS NOT NULL AND expires_at <= ?`,
+		time.Now().UnixNano(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// rangeBounds iterates over keys in [lower, upper), nil bound means the range is unbounded on that side.
+// Keys are compared as blobs, so the order is the same bytewise order as in other ordered stores.
+func rangeBounds(ctx context.Context, q querier, table string, lower, upper []byte, reverse, values bool, iter func(k, v []byte) error) error {
+	now := time.Now().UnixNano()
+	lowerInclusive := true
+
+	for {
+		query, args := rangeQuery(table, lower, lowerInclusive, upper, reverse, values, now)
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		type row struct{ k, v []byte }
+		page := make([]row, 0, pageSize)
+		for rows.Next() {
+			var r row
+			if values {
+				err = rows.Scan(&r.k, &r.v)
+			} else {
+				err = rows.Scan(&r.k)
+			}
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			page = append(page, r)
+		}
+		if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+			return err
+		}
+
+		for _, r := range page {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// the error is returned as is, as iterators stop with sentinel errors such as io.EOF
+			if err := iter(r.k, r.v); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+		last := page[len(page)-1].k
+		if reverse {
+			upper = last
+		} else {
+			lower, lowerInclusive = last, false
+		}
+	}
+}
+
+func rangeQuery(table string, lower []byte, lowerInclusive bool, upper []byte, reverse, values bool, now int64) (string, []any) {
+	var query strings.Builder
+	query.WriteString("SELECT key")
+	if values {
+		query.WriteString(", value")
+	}
+	query.WriteString(" FROM " + quoteIdent(table) + " WHERE (expires_at IS NULL OR expires_at > ?)")
+	args := []any{now}
+
+	if lower != nil {
+		if lowerInclusive {
+			query.WriteString(" AND key >= ?")
+		} else {
+			query.WriteString(" AND key > ?")
+		}
+		args = append(args, blob(lower))
+	}
+	if upper != nil {
+		query.WriteString(" AND key < ?")
+		args = append(args, blob(upper))
+	}
+
+	if reverse {
+		query.WriteString(" ORDER BY key DESC")
+	} else {
+		query.WriteString(" ORDER BY key")
+	}
+	query.WriteString(" LIMIT ?")
+	args = append(args, pageSize)
+
+	return query.String(), args
+}
+
+func rangeValues[K kv.Bytes, V any](ctx context.Context, q querier, table string, lower, upper []byte, reverse bool, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeBounds(ctx, q, table, lower, upper, reverse, true, func(k, data []byte) error {
+		var v V
+		if err := codec.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		return iter(K(k), v)
+	})
+}
+
+func rangePrefix[K kv.Bytes, V any](ctx context.Context, q querier, table string, prefix []byte, codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, q, table, nilIfEmpty(prefix), prefixEnd(prefix), false, codec, iter)
+}
+
+func rangeOrdered[K kv.Bytes, V any](ctx context.Context, q querier, table string, order kv.Order[K], codec kv.Codec[V], iter kv.Iter[K, V]) error {
+	return rangeValues(ctx, q, table, nilIfEmpty([]byte(order.Min)), nilIfEmpty([]byte(order.Max)), order.Reverse, codec, iter)
+}
+
+func rangeKeys[K kv.Bytes](ctx context.Context, q querier, table string, prefix []byte, iter kv.KeyIter[K]) error {
+	return rangeBounds(ctx, q, table, nilIfEmpty(prefix), prefixEnd(prefix), false, false, func(k, _ []byte) error {
+		return iter(K(k))
+	})
+}
+
+func count(ctx context.Context, q querier, table string, prefix []byte) (int, error) {
+	query := `SELECT COUNT(*) FROM ` + quoteIdent(table) + ` WHERE (expires_at IS NULL OR expires_at > ?)`
+	args := []any{time.Now().UnixNano()}
+	if len(prefix) > 0 {
+		query += " AND key >= ?"
+		args = append(args, blob(prefix))
+	}
+	if end := prefixEnd(prefix); end != nil {
+		query += " AND key < ?"
+		args = append(args, blob(end))
+	}
+
+	var n int
+	err := q.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// prefixEnd returns the smallest key greater than all keys with the prefix, nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func nilIfEmpty(k []byte) []byte {
+	if len(k) == 0 {
+		return nil
+	}
+	return k
+}
+
+// blob makes sure the slice is bound as an empty blob and not as NULL.
+func blob(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,365 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrLeaseLost         = errors.New("lease lost")
+	ErrInvalidLeaseTTL   = errors.New("lease ttl must be positive")
+	ErrInvalidLeaseValue = errors.New("invalid lease record")
+)
+
+// Lease is a lock held for a limited time, it is renewed in the background until released.
+type Lease interface {
+	// Token returns the fencing token of the lease,
+	// it is greater than tokens of all previous leases of the same key.
+	Token() uint64
+	// Lost returns a channel closed when the lease is lost or released.
+	// A lease is lost when it wasn't renewed before its expiration, so another holder may have acquired it.
+	Lost() <-chan struct{}
+	// Release stops renewal and releases the lock, it returns ErrLeaseLost if the lease was lost.
+	Release(ctx context.Context) error
+}
+
+// LeaseLocks is an interface for locks which expire unless their holder renews them,
+// so a crashed holder doesn't keep the lock forever.
+type LeaseLocks[K any] interface {
+	// Lock blocks until the lock is acquired or the context is done.
+	// The lease expires after ttl unless it is renewed, which is done automatically.
+	Lock(ctx context.Context, key K, ttl time.Duration) (Lease, error)
+
+	// closing lock storage and releasing all leases
+	Close(ctx context.Context) error
+}
+
+// leaseLostMarginDivisor sets the fraction of ttl by which a lease which can't be renewed is reported lost before it expires.
+const leaseLostMarginDivisor = 10
+
+// minLeaseRenewInterval bounds the renewal interval of leases with a tiny ttl, which are lost on the first renewal.
+const minLeaseRenewInterval = time.Millisecond
+
+// NewLease creates a lease renewed every third of ttl with the renew function until it is released.
+// It is a helper for implementations of [LeaseLocks].
+//
+// Renew must extend the lease to ttl from the call, returning ErrLeaseLost if the lease is not held anymore.
+// Other errors are retried until shortly before the lease expires, when it is reported lost.
+// Release is called by [Lease.Release] even if the lease was lost, so the implementation can clean up its state.
+func NewLease(token uint64, ttl time.Duration, renew func(ctx context.Context) error, release func(ctx context.Context) error) Lease {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &lease{
+		token:   token,
+		ttl:     ttl,
+		renew:   renew,
+		release: release,
+		lost:    make(chan struct{}),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go l.keepAlive(ctx)
+	return l
+}
+
+type lease struct {
+	token   uint64
+	ttl     time.Duration
+	renew   func(ctx context.Context) error
+	release func(ctx context.Context) error
+
+	lostOnce sync.Once
+	lost     chan struct{}
+	isLost   bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Token implements Lease.
+func (l *lease) Token() uint64 {
+	return l.token
+}
+
+// Lost implements Lease.
+func (l *lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release implements Lease.
+func (l *lease) Release(ctx context.Context) error {
+	l.cancel()
+	<-l.done
+
+	l.close()
+	err := l.release(ctx)
+	if l.isLost {
+		return ErrLeaseLost
+	}
+	return err
+}
+
+func (l *lease) keepAlive(ctx context.Context) {
+	defer close(l.done)
+
+	interval := max(l.ttl/3, minLeaseRenewInterval)
+	// the lease is reported lost a margin before it expires, while no other holder can have acquired it yet
+	margin := l.ttl / leaseLostMarginDivisor
+	lostAt := time.Now().Add(l.ttl - margin)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lost := time.NewTimer(time.Until(lostAt))
+	defer lost.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-lost.C:
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		if !start.Before(lostAt) {
+			l.markLost()
+			return
+		}
+
+		renewCtx, cancel := context.WithDeadline(ctx, lostAt)
+		err := l.renew(renewCtx)
+		cancel()
+		switch {
+		case err == nil:
+			lostAt = start.Add(l.ttl - margin)
+			if !lost.Stop() {
+				// drain a tick of a timer which fired during the renewal
+				select {
+				case <-lost.C:
+				default:
+				}
+			}
+			lost.Reset(time.Until(lostAt))
+		case errors.Is(err, ErrLeaseLost):
+			l.markLost()
+			return
+		case ctx.Err() != nil:
+			return
+		}
+	}
+}
+
+func (l *lease) markLost() {
+	l.isLost = true
+	l.close()
+}
+
+func (l *lease) close() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+const leaseRecordSize = 16
+
+// LeaseRecord is a state of a lease stored by [NewStoreLeaseLocks].
+// A released lease keeps its token with zero expiration time, so tokens keep increasing.
+// Its binary form is 8 bytes of big-endian token followed by 8 bytes of big-endian expiration time in unix nanoseconds.
+type LeaseRecord struct {
+	Token     uint64
+	ExpiresAt time.Time
+}
+
+var _ Binary = (*LeaseRecord)(nil)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (r LeaseRecord) MarshalBinary() ([]byte, error) {
+	data := make([]byte, leaseRecordSize)
+	binary.BigEndian.PutUint64(data[:8], r.Token)
+	if !r.ExpiresAt.IsZero() {
+		binary.BigEndian.PutUint64(data[8:], uint64(r.ExpiresAt.UnixNano()))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *LeaseRecord) UnmarshalBinary(data []byte) error {
+	if len(data) != leaseRecordSize {
+		return ErrInvalidLeaseValue
+	}
+
+	r.Token = binary.BigEndian.Uint64(data[:8])
+	r.ExpiresAt = time.Time{}
+	if exp := int64(binary.BigEndian.Uint64(data[8:])); exp != 0 {
+		r.ExpiresAt = time.Unix(0, exp)
+	}
+	return nil
+}
+
+// Held reports whether the lease is held at the given time.
+func (r LeaseRecord) Held(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && now.Before(r.ExpiresAt)
+}
+
+// NewStoreLeaseLocks creates [LeaseLocks] on top of any store, polling a held lock with the given interval.
+//
+// If the store implements [TransactionalStore], lease records are updated with conditional writes in transactions,
+// so the locks are shared by all processes using the store. Otherwise the locks serialize their own updates,
+// and concurrent acquisitions from different processes may both succeed.
+func NewStoreLeaseLocks[K any](store Store[K, LeaseRecord], poll time.Duration) LeaseLocks[K] {
+	return &storeLeaseLocks[K]{
+		store:  store,
+		poll:   poll,
+		leases: map[Lease]struct{}{},
+	}
+}
+
+type storeLeaseLocks[K any] struct {
+	mu    sync.Mutex
+	store Store[K, LeaseRecord]
+	poll  time.Duration
+
+	leasesMu sync.Mutex
+	leases   map[Lease]struct{}
+}
+
+// Lock implements LeaseLocks.
+func (l *storeLeaseLocks[K]) Lock(ctx context.Context, key K, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return nil, ErrInvalidLeaseTTL
+	}
+
+	for {
+		var token uint64
+		err := l.update(ctx, key, func(r LeaseRecord, now time.Time) (LeaseRecord, bool) {
+			if r.Held(now) {
+				return r, false
+			}
+			token = r.Token + 1
+			return LeaseRecord{Token: token, ExpiresAt: now.Add(ttl)}, true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if token != 0 {
+			var lease Lease
+			l.leasesMu.Lock()
+			defer l.leasesMu.Unlock()
+			lease = NewLease(token, ttl,
+				func(ctx context.Context) error {
+					return l.renew(ctx, key, token, ttl)
+				},
+				func(ctx context.Context) error {
+					l.leasesMu.Lock()
+					delete(l.leases, lease)
+					l.leasesMu.Unlock()
+					return l.release(ctx, key, token)
+				},
+			)
+			l.leases[lease] = struct{}{}
+			return lease, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(l.poll):
+		}
+	}
+}
+
+// Close implements LeaseLocks.
+func (l *storeLeaseLocks[K]) Close(ctx context.Context) error {
+	l.leasesMu.Lock()
+	leases := make([]Lease, 0, len(l.leases))
+	for lease := range l.leases {
+		leases = append(leases, lease)
+	}
+	l.leasesMu.Unlock()
+
+	var errs []error
+	for _, lease := range leases {
+		err := lease.Release(ctx)
+		if err != nil && !errors.Is(err, ErrLeaseLost) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *storeLeaseLocks[K]) renew(ctx context.Context, key K, token uint64, ttl time.Duration) error {
+	renewed := false
+	err := l.update(ctx, key, func(r LeaseRecord, now time.Time) (LeaseRecord, bool) {
+		if r.Token != token || r.ExpiresAt.IsZero() {
+			return r, false
+		}
+		renewed = true
+		return LeaseRecord{Token: token, ExpiresAt: now.Add(ttl)}, true
+	})
+	if err != nil {
+		return err
+	}
+	if !renewed {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (l *storeLeaseLocks[K]) release(ctx context.Context, key K, token uint64) error {
+	released := false
+	err := l.update(ctx, key, func(r LeaseRecord, now time.Time) (LeaseRecord, bool) {
+		if r.Token != token || !r.Held(now) {
+			return r, false
+		}
+		released = true
+		return LeaseRecord{Token: token}, true
+	})
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// update applies the function to the lease record of the key and writes the result if the function returns true.
+// A missing record is passed as a zero record.
+func (l *storeLeaseLocks[K]) update(ctx context.Context, key K, f func(r LeaseRecord, now time.Time) (LeaseRecord, bool)) error {
+	ts, ok := l.store.(TransactionalStore[K, LeaseRecord])
+	if !ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.updateIn(ctx, l.store, key, f)
+	}
+
+	for {
+		tx, err := ts.Transaction(true)
+		if err != nil {
+			return err
+		}
+
+		err = errors.Join(l.updateIn(ctx, tx, key, f), tx.Close(ctx))
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *storeLeaseLocks[K]) updateIn(ctx context.Context, s Store[K, LeaseRecord], key K, f func(r LeaseRecord, now time.Time) (LeaseRecord, bool)) error {
+	r, err := s.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+
+	r, write := f(r, time.Now())
+	if !write {
+		return nil
+	}
+	return s.Set(ctx, key, r)
+}
@@ -0,0 +1,52 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// UnlockFunc releases locks acquired together, calls after the first one do nothing.
+type UnlockFunc func(ctx context.Context) error
+
+// LockMany acquires the locks of all keys, waiting for each of them as [Locks.Lock] does.
+//
+// Keys are deduplicated and acquired in the byte order, so concurrent LockMany calls with overlapping keys
+// can't deadlock each other. If any lock can't be acquired, the already acquired ones are released.
+func LockMany[K Bytes](ctx context.Context, locks Locks[K], keys ...K) (UnlockFunc, error) {
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, func(a, b K) int {
+		return strings.Compare(string(a), string(b))
+	})
+	keys = slices.CompactFunc(keys, func(a, b K) bool {
+		return string(a) == string(b)
+	})
+
+	acquired := make([]K, 0, len(keys))
+	unlock := func(ctx context.Context) error {
+		var errs []error
+		for i := len(acquired) - 1; i >= 0; i-- {
+			errs = append(errs, locks.Unlock(ctx, acquired[i]))
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, k := range keys {
+		err := locks.Lock(ctx, k)
+		if err != nil {
+			return nil, errors.Join(err, unlock(context.WithoutCancel(ctx)))
+		}
+		acquired = append(acquired, k)
+	}
+
+	once := sync.Once{}
+	return func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			err = unlock(ctx)
+		})
+		return err
+	}, nil
+}
@@ -0,0 +1,56 @@
+package kv
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrLockNotHeld       = errors.New("lock is not held")
+	ErrInvalidLockRecord = errors.New("invalid lock record")
+)
+
+// LockRecord is a state of an exclusive lock stored by backends without native locks.
+// A record which expired is stale and can be reclaimed by another owner,
+// so a crashed holder doesn't keep the lock forever.
+// Its binary form is shared across all backends: 8 bytes of big-endian expiration time
+// in unix nanoseconds followed by the owner ID.
+type LockRecord struct {
+	Owner     string
+	ExpiresAt time.Time
+}
+
+var _ Binary = (*LockRecord)(nil)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (r LockRecord) MarshalBinary() ([]byte, error) {
+	data := binary.BigEndian.AppendUint64(nil, uint64(r.ExpiresAt.UnixNano()))
+	return append(data, r.Owner...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *LockRecord) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return ErrInvalidLockRecord
+	}
+
+	r.ExpiresAt = time.Unix(0, int64(binary.BigEndian.Uint64(data[:8])))
+	r.Owner = string(data[8:])
+	return nil
+}
+
+// Held reports whether the lock is held at the given time.
+func (r LockRecord) Held(now time.Time) bool {
+	return now.Before(r.ExpiresAt)
+}
+
+// NewLockOwner returns a random owner ID for lock records.
+func NewLockOwner() string {
+	id := make([]byte, 16)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
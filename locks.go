@@ -3,9 +3,22 @@ package kv
 import "context"
 
 type Locks[K any] interface {
+	// Lock blocks until the lock is acquired, it returns the context error if the context is done first.
 	Lock(ctx context.Context, key K) error
+	// TryLock acquires the lock only if it is free, it returns false without waiting otherwise.
+	TryLock(ctx context.Context, key K) (bool, error)
 	Unlock(ctx context.Context, key K) error
 
 	// closing lock storage and releasing all locks
 	Close(ctx context.Context) error
 }
+
+// RWLocks is an interface for locks which can be shared by readers or held exclusively by a single writer.
+// Lock, TryLock and Unlock of the embedded Locks acquire and release the exclusive lock.
+type RWLocks[K any] interface {
+	Locks[K]
+
+	// RLock blocks until the shared lock is acquired, it returns the context error if the context is done first.
+	RLock(ctx context.Context, key K) error
+	RUnlock(ctx context.Context, key K) error
+}
@@ -2,6 +2,9 @@ package kv
 
 import "context"
 
+// Order describes a key range and the direction of an ordered iteration.
+// Keys are iterated in the half-open range [Min, Max).
+// Zero value of Min or Max means the range is not bounded from that side.
 type Order[K any] struct {
 	Min     K
 	Max     K
@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid page cursor")
+	ErrInvalidLimit  = errors.New("page limit must be positive")
+)
+
+// PageOptions describes a single page request for [RangePage].
+type PageOptions struct {
+	// Limit is the maximum number of items returned in the page, must be positive.
+	Limit int
+	// Cursor is the continuation token returned in [Page.Next] of the previous page.
+	// Empty cursor requests the first page.
+	Cursor string
+	// Reverse iterates the keys in descending order.
+	Reverse bool
+}
+
+// Item is a key-value pair.
+type Item[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// Page is a result of [RangePage].
+type Page[K, V any] struct {
+	Items []Item[K, V]
+	// Next is an opaque continuation token for the next page.
+	// It is empty when there are no more items.
+	Next string
+}
+
+// RangePage returns a page of key-value pairs ordered by key, starting after the given cursor.
+//
+// If the store implements [StoreOrdered], the page is read with a single ordered range seeking directly to the cursor.
+// Otherwise RangePage falls back to iterating and sorting the whole store on every call,
+// which is only suitable for small unordered stores such as in-memory ones.
+func RangePage[K Bytes, V any](ctx context.Context, s Store[K, V], opts PageOptions) (Page[K, V], error) {
+	if opts.Limit <= 0 {
+		return Page[K, V]{}, ErrInvalidLimit
+	}
+
+	cursor, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return Page[K, V]{}, err
+	}
+
+	// one extra item is read to know if there is a next page
+	var items []Item[K, V]
+	if so, ok := s.(StoreOrdered[K, V]); ok {
+		items, err = rangePageOrdered(ctx, so, opts, cursor)
+	} else {
+		items, err = rangePageSorted(ctx, s, opts, cursor)
+	}
+	if err != nil {
+		return Page[K, V]{}, err
+	}
+
+	page := Page[K, V]{Items: items}
+	if len(items) > opts.Limit {
+		page.Items = items[:opts.Limit]
+		page.Next = encodeCursor(page.Items[opts.Limit-1].Key)
+	}
+
+	return page, nil
+}
+
+func rangePageOrdered[K Bytes, V any](ctx context.Context, s StoreOrdered[K, V], opts PageOptions, cursor []byte) ([]Item[K, V], error) {
+	order := Order[K]{Reverse: opts.Reverse}
+	if cursor != nil {
+		if opts.Reverse {
+			order.Max = K(cursor)
+		} else {
+			// smallest key strictly greater than cursor
+			order.Min = K(append(cursor, 0))
+		}
+	}
+
+	items := make([]Item[K, V], 0, opts.Limit+1)
+	err := s.RangeOrdered(ctx, order, func(k K, v V) error {
+		items = append(items, Item[K, V]{Key: k, Value: v})
+		if len(items) > opts.Limit {
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func rangePageSorted[K Bytes, V any](ctx context.Context, s Store[K, V], opts PageOptions, cursor []byte) ([]Item[K, V], error) {
+	items := []Item[K, V]{}
+	err := s.Range(ctx, func(k K, v V) error {
+		if cursor != nil {
+			c := strings.Compare(string(k), string(cursor))
+			if (!opts.Reverse && c <= 0) || (opts.Reverse && c >= 0) {
+				return nil
+			}
+		}
+		items = append(items, Item[K, V]{Key: k, Value: v})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(items, func(a, b Item[K, V]) int {
+		if opts.Reverse {
+			return strings.Compare(string(b.Key), string(a.Key))
+		}
+		return strings.Compare(string(a.Key), string(b.Key))
+	})
+
+	if len(items) > opts.Limit+1 {
+		items = items[:opts.Limit+1]
+	}
+
+	return items, nil
+}
+
+// cursorVersion is the first byte of an encoded cursor, so a cursor of the empty key is not an empty token.
+const cursorVersion = 1
+
+func encodeCursor[K Bytes](k K) string {
+	return base64.RawURLEncoding.EncodeToString(append([]byte{cursorVersion}, k...))
+}
+
+func decodeCursor(cursor string) ([]byte, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCursor, err)
+	}
+	if len(data) == 0 || data[0] != cursorVersion {
+		return nil, ErrInvalidCursor
+	}
+	return data[1:], nil
+}
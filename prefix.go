@@ -56,6 +56,30 @@ func (p *prefixBytesStore[K, V]) RangeWithPrefix(ctx context.Context, k K, iter
 	})
 }
 
+// RangeKeys implements StoreKeys.
+func (p *prefixBytesStore[K, V]) RangeKeys(ctx context.Context, iter KeyIter[K]) error {
+	return RangeKeysWithPrefix(ctx, p.store, p.prefix, func(k K) error {
+		return iter(p.cutPrefix(k))
+	})
+}
+
+// RangeKeysWithPrefix implements StoreKeys.
+func (p *prefixBytesStore[K, V]) RangeKeysWithPrefix(ctx context.Context, k K, iter KeyIter[K]) error {
+	return RangeKeysWithPrefix(ctx, p.store, p.withPrefix(k), func(k K) error {
+		return iter(p.cutPrefix(k))
+	})
+}
+
+// Has implements StoreHas.
+func (p *prefixBytesStore[K, V]) Has(ctx context.Context, k K) (bool, error) {
+	return Has(ctx, p.store, p.withPrefix(k))
+}
+
+// Count implements StoreCount.
+func (p *prefixBytesStore[K, V]) Count(ctx context.Context, k K) (int, error) {
+	return Count(ctx, p.store, p.withPrefix(k))
+}
+
 // Get implements Store.
 func (p *prefixBytesStore[K, V]) Edit(ctx context.Context, k K, edit Edit[V]) error {
 	return p.store.Edit(ctx, p.withPrefix(k), edit)
@@ -0,0 +1,439 @@
+// Package queue implements a durable FIFO queue on top of ordered transactional key-value stores.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+var (
+	// ErrEmpty is returned by [Queue.TryDequeue] when there are no visible messages.
+	ErrEmpty = errors.New("queue is empty")
+	// ErrLeaseLost is returned by [Queue.Ack] and [Queue.Nack] when the message visibility timeout has expired
+	// and the message was redelivered or dead-lettered.
+	ErrLeaseLost = errors.New("message lease lost")
+)
+
+// Store is a store capable of holding a queue.
+type Store interface {
+	kv.Store[string, []byte]
+	kv.StoreOrdered[string, []byte]
+	kv.TransactionalStore[string, []byte]
+}
+
+type Options[T any] struct {
+	Codec kv.Codec[T]
+	// VisibilityTimeout is a time a dequeued message is hidden from other consumers, until it is acknowledged.
+	VisibilityTimeout time.Duration
+	// MaxAttempts is a number of deliveries after which the message is moved to dead letters, zero means unlimited.
+	MaxAttempts int
+	// PollInterval is an interval of store polling by blocking [Queue.Dequeue].
+	PollInterval time.Duration
+}
+
+func DefaultOptions[T any]() Options[T] {
+	return Options[T]{
+		Codec:             kv.CodecJSON[T]{},
+		VisibilityTimeout: 30 * time.Second,
+		MaxAttempts:       5,
+		PollInterval:      time.Second,
+	}
+}
+
+// Message is a dequeued message.
+type Message[T any] struct {
+	ID         string
+	Payload    T
+	Attempts   int
+	EnqueuedAt time.Time
+
+	// visibleAt identifies the delivery of the message
+	visibleAt int64
+}
+
+// Queue is a durable FIFO queue with at-least-once delivery.
+//
+// Messages are ordered by the time they become visible, dequeued message becomes invisible for the visibility timeout
+// and is redelivered if it is not acknowledged in time.
+type Queue[T any] struct {
+	store  Store
+	prefix string
+	opts   Options[T]
+
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+// New creates a queue with the given name in the store, multiple queues can share the same store.
+func New[T any](store Store, name string, opts Options[T]) *Queue[T] {
+	return &Queue[T]{
+		store:  store,
+		prefix: name + "/",
+		opts:   opts,
+		wake:   make(chan struct{}),
+	}
+}
+
+type record struct {
+	Payload    []byte
+	Attempts   int
+	EnqueuedAt int64
+	VisibleAt  int64
+}
+
+var recordCodec = kv.CodecJSON[record]{}
+
+// Enqueue adds a message to the queue and returns its ID.
+func (q *Queue[T]) Enqueue(ctx context.Context, payload T) (string, error) {
+	return q.EnqueueDelayed(ctx, payload, 0)
+}
+
+// EnqueueDelayed adds a message to the queue, which becomes visible after the given delay.
+func (q *Queue[T]) EnqueueDelayed(ctx context.Context, payload T, delay time.Duration) (string, error) {
+	data, err := q.opts.Codec.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	id, err := newID(now)
+	if err != nil {
+		return "", err
+	}
+	rec := record{
+		Payload:    data,
+		EnqueuedAt: now.UnixNano(),
+		VisibleAt:  now.Add(delay).UnixNano(),
+	}
+
+	err = q.update(ctx, func(tx *kv.Batch[string, []byte]) error {
+		return q.setRecord(ctx, tx, id, rec)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	q.signal()
+	return id, nil
+}
+
+// TryDequeue returns the first visible message or [ErrEmpty] without waiting.
+func (q *Queue[T]) TryDequeue(ctx context.Context) (Message[T], error) {
+	for {
+		now := time.Now()
+
+		// candidates are collected before leasing, because a store may not allow a transaction inside a range
+		candidates := []candidate{}
+		err := q.store.RangeOrdered(ctx, kv.Order[string]{
+			Min: q.prefix + "v/",
+			Max: q.prefix + "v/" + encodeTime(now.UnixNano()+1),
+		}, func(k string, v []byte) error {
+			candidates = append(candidates, candidate{indexKey: k, id: string(v)})
+			if len(candidates) >= 16 {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			return Message[T]{}, err
+		}
+		if len(candidates) == 0 {
+			return Message[T]{}, ErrEmpty
+		}
+
+		for _, c := range candidates {
+			msg, ok, err := q.lease(ctx, c, now)
+			if err != nil {
+				return Message[T]{}, err
+			}
+			if ok {
+				return msg, nil
+			}
+		}
+	}
+}
+
+// Dequeue returns the first visible message, waiting for it until the context is done.
+func (q *Queue[T]) Dequeue(ctx context.Context) (Message[T], error) {
+	for {
+		q.mu.Lock()
+		wake := q.wake
+		q.mu.Unlock()
+
+		msg, err := q.TryDequeue(ctx)
+		if !errors.Is(err, ErrEmpty) {
+			return msg, err
+		}
+
+		timer := time.NewTimer(q.opts.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Message[T]{}, ctx.Err()
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Ack acknowledges the message processing and removes it from the queue.
+func (q *Queue[T]) Ack(ctx context.Context, msg Message[T]) error {
+	return q.update(ctx, func(tx *kv.Batch[string, []byte]) error {
+		rec, err := q.leasedRecord(ctx, tx, msg)
+		if err != nil {
+			return err
+		}
+
+		return q.deleteRecord(ctx, tx, msg.ID, rec)
+	})
+}
+
+// Nack returns the message to the queue, making it visible after the given delay.
+// The message is moved to dead letters if it has reached max attempts.
+func (q *Queue[T]) Nack(ctx context.Context, msg Message[T], delay time.Duration) error {
+	err := q.update(ctx, func(tx *kv.Batch[string, []byte]) error {
+		rec, err := q.leasedRecord(ctx, tx, msg)
+		if err != nil {
+			return err
+		}
+
+		if q.exhausted(rec) {
+			return q.deadLetter(ctx, tx, msg.ID, rec)
+		}
+
+		err = tx.Delete(ctx, q.indexKey(msg.ID, rec.VisibleAt))
+		if err != nil {
+			return err
+		}
+		rec.VisibleAt = time.Now().Add(delay).UnixNano()
+		return q.setRecord(ctx, tx, msg.ID, rec)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.signal()
+	return nil
+}
+
+// RangeDeadLetters iterates over messages moved to dead letters after max attempts.
+func (q *Queue[T]) RangeDeadLetters(ctx context.Context, iter func(msg Message[T]) error) error {
+	prefix := q.prefix + "d/"
+	return q.store.RangeWithPrefix(ctx, prefix, func(k string, data []byte) error {
+		var rec record
+		err := recordCodec.Unmarshal(data, &rec)
+		if err != nil {
+			return err
+		}
+
+		msg, err := q.message(k[len(prefix):], rec)
+		if err != nil {
+			return err
+		}
+		return iter(msg)
+	})
+}
+
+// DeleteDeadLetter removes the message from dead letters.
+func (q *Queue[T]) DeleteDeadLetter(ctx context.Context, id string) error {
+	return q.store.Delete(ctx, q.deadKey(id))
+}
+
+var errStop = errors.New("stop")
+
+type candidate struct {
+	indexKey string
+	id       string
+}
+
+// lease makes the message invisible for the visibility timeout,
+// it returns false if the message was taken by another consumer or moved to dead letters.
+func (q *Queue[T]) lease(ctx context.Context, c candidate, now time.Time) (Message[T], bool, error) {
+	var rec record
+	leased := false
+	id := c.id
+
+	err := q.update(ctx, func(tx *kv.Batch[string, []byte]) error {
+		_, err := tx.Get(ctx, c.indexKey)
+		if errors.Is(err, kv.ErrKeyNotFound) {
+			// taken by another consumer
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rec, err = q.getRecord(ctx, tx, id)
+		if errors.Is(err, kv.ErrKeyNotFound) {
+			// index entry without a message is never expected, but it must not block the queue
+			return tx.Delete(ctx, c.indexKey)
+		}
+		if err != nil {
+			return err
+		}
+		if rec.VisibleAt > now.UnixNano() {
+			return nil
+		}
+
+		if q.exhausted(rec) {
+			return q.deadLetter(ctx, tx, id, rec)
+		}
+
+		err = tx.Delete(ctx, q.indexKey(id, rec.VisibleAt))
+		if err != nil {
+			return err
+		}
+		rec.Attempts++
+		rec.VisibleAt = now.Add(q.opts.VisibilityTimeout).UnixNano()
+		err = q.setRecord(ctx, tx, id, rec)
+		if err != nil {
+			return err
+		}
+
+		leased = true
+		return nil
+	})
+	if errors.Is(err, kv.ErrConflict) {
+		return Message[T]{}, false, nil
+	}
+	if err != nil || !leased {
+		return Message[T]{}, false, err
+	}
+
+	// a payload which can't be decoded stays leased, so it is redelivered and eventually dead-lettered
+	msg, err := q.message(id, rec)
+	return msg, err == nil, err
+}
+
+func (q *Queue[T]) exhausted(rec record) bool {
+	return q.opts.MaxAttempts > 0 && rec.Attempts >= q.opts.MaxAttempts
+}
+
+func (q *Queue[T]) deadLetter(ctx context.Context, tx *kv.Batch[string, []byte], id string, rec record) error {
+	err := q.deleteRecord(ctx, tx, id, rec)
+	if err != nil {
+		return err
+	}
+
+	data, err := recordCodec.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return tx.Set(ctx, q.deadKey(id), data)
+}
+
+func (q *Queue[T]) leasedRecord(ctx context.Context, tx *kv.Batch[string, []byte], msg Message[T]) (record, error) {
+	rec, err := q.getRecord(ctx, tx, msg.ID)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return rec, ErrLeaseLost
+	}
+	if err != nil {
+		return rec, err
+	}
+	if rec.VisibleAt != msg.visibleAt || rec.VisibleAt <= time.Now().UnixNano() {
+		return rec, ErrLeaseLost
+	}
+	return rec, nil
+}
+
+func (q *Queue[T]) getRecord(ctx context.Context, tx *kv.Batch[string, []byte], id string) (record, error) {
+	var rec record
+	data, err := tx.Get(ctx, q.messageKey(id))
+	if err != nil {
+		return rec, err
+	}
+	err = recordCodec.Unmarshal(data, &rec)
+	return rec, err
+}
+
+func (q *Queue[T]) setRecord(ctx context.Context, tx *kv.Batch[string, []byte], id string, rec record) error {
+	data, err := recordCodec.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Set(ctx, q.messageKey(id), data)
+	if err != nil {
+		return err
+	}
+	return tx.Set(ctx, q.indexKey(id, rec.VisibleAt), []byte(id))
+}
+
+func (q *Queue[T]) deleteRecord(ctx context.Context, tx *kv.Batch[string, []byte], id string, rec record) error {
+	err := tx.Delete(ctx, q.indexKey(id, rec.VisibleAt))
+	if err != nil {
+		return err
+	}
+	return tx.Delete(ctx, q.messageKey(id))
+}
+
+func (q *Queue[T]) message(id string, rec record) (Message[T], error) {
+	msg := Message[T]{
+		ID:         id,
+		Attempts:   rec.Attempts,
+		EnqueuedAt: time.Unix(0, rec.EnqueuedAt),
+		visibleAt:  rec.VisibleAt,
+	}
+	err := q.opts.Codec.Unmarshal(rec.Payload, &msg.Payload)
+	return msg, err
+}
+
+// update runs the function in an update transaction, its writes are applied only if it succeeds.
+// A conflict isn't retried, so the caller can tell a message taken by another consumer.
+func (q *Queue[T]) update(ctx context.Context, f func(tx *kv.Batch[string, []byte]) error) error {
+	tx, err := q.store.Transaction(true)
+	if err != nil {
+		return err
+	}
+
+	b := kv.NewBatch(tx)
+	err = f(b)
+	if err == nil {
+		err = b.Apply(ctx)
+	}
+	return errors.Join(err, tx.Close(ctx))
+}
+
+func (q *Queue[T]) signal() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+func (q *Queue[T]) messageKey(id string) string {
+	return q.prefix + "m/" + id
+}
+
+func (q *Queue[T]) indexKey(id string, visibleAt int64) string {
+	return q.prefix + "v/" + encodeTime(visibleAt) + id
+}
+
+func (q *Queue[T]) deadKey(id string) string {
+	return q.prefix + "d/" + id
+}
+
+// encodeTime encodes unix nanoseconds preserving the order of keys.
+func encodeTime(t int64) string {
+	return string(binary.BigEndian.AppendUint64(nil, uint64(t)))
+}
+
+// newID creates a unique message ID, IDs are ordered by the creation time.
+func newID(now time.Time) (string, error) {
+	id := binary.BigEndian.AppendUint64(nil, uint64(now.UnixNano()))
+	id = append(id, make([]byte, 8)...)
+	_, err := rand.Read(id[8:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
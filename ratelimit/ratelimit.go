@@ -0,0 +1,118 @@
+// Package ratelimit implements token bucket and sliding window rate limiters on top of key-value stores.
+//
+// If the store implements kv.TransactionalStore, every state update is a single transaction retried on conflicts,
+// so the limit is shared by all processes using the store. Otherwise a limiter serializes only its own updates,
+// so the limit is per process, unless the limiter is wrapped with [NewLocked] over locks shared by the processes.
+//
+// States are written with kv.StoreTTL when the store implements it, expiring once they are equal to a missing state,
+// so keys which are no longer limited don't stay in the store.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+var (
+	ErrInvalidN     = errors.New("n must be positive")
+	ErrInvalidState = errors.New("invalid rate limiter state")
+)
+
+// Limiter limits the rate of events per key.
+type Limiter[K any] interface {
+	// Allow reports whether n events may happen now for the key and consumes them if so.
+	Allow(ctx context.Context, key K, n int) (bool, error)
+}
+
+// Clock returns the current time, it is replaceable for tests.
+// A nil Clock is time.Now.
+type Clock func() time.Time
+
+func (c Clock) Now() time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c()
+}
+
+// newStoreLimiter creates a limiter keeping states in the store, take calculates a new state of the key
+// and ttl returns the time after which the state is equal to a missing one, non-positive if it never is.
+func newStoreLimiter[K any, S any](store kv.Store[K, S], clock Clock, take func(s S, n int, now time.Time) (S, bool), ttl func(s S, now time.Time) time.Duration) Limiter[K] {
+	return &storeLimiter[K, S]{
+		store: store,
+		clock: clock,
+		take:  take,
+		ttl:   ttl,
+	}
+}
+
+type storeLimiter[K, S any] struct {
+	mu    sync.Mutex
+	store kv.Store[K, S]
+	clock Clock
+	take  func(s S, n int, now time.Time) (S, bool)
+	ttl   func(s S, now time.Time) time.Duration
+}
+
+// Allow implements Limiter.
+func (l *storeLimiter[K, S]) Allow(ctx context.Context, key K, n int) (bool, error) {
+	if n <= 0 {
+		return false, ErrInvalidN
+	}
+
+	if _, ok := l.store.(kv.TransactionalStore[K, S]); !ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+	}
+
+	var allowed bool
+	err := kv.Update(ctx, l.store, func(tx kv.Store[K, S]) error {
+		return l.update(ctx, tx, key, n, &allowed)
+	})
+	return allowed, err
+}
+
+func (l *storeLimiter[K, S]) update(ctx context.Context, tx kv.Store[K, S], key K, n int, allowed *bool) error {
+	s, err := tx.Get(ctx, key)
+	if err != nil && !errors.Is(err, kv.ErrKeyNotFound) {
+		return err
+	}
+
+	now := l.clock.Now()
+	s, *allowed = l.take(s, n, now)
+	if !*allowed {
+		// a denied request doesn't consume anything, the state is recalculated on the next call
+		return nil
+	}
+	return kv.SetWithTTL(ctx, tx, key, s, l.ttl(s, now))
+}
+
+// NewLocked wraps the limiter, so every Allow holds the lock of the key.
+// A limiter over a store without transactions limits only its own process,
+// locks shared by all processes, such as kvolric or kvredis locks, make the limit shared too.
+func NewLocked[K any](l Limiter[K], locks kv.Locks[K]) Limiter[K] {
+	return &lockedLimiter[K]{
+		limiter: l,
+		locks:   locks,
+	}
+}
+
+type lockedLimiter[K any] struct {
+	limiter Limiter[K]
+	locks   kv.Locks[K]
+}
+
+// Allow implements Limiter.
+func (l *lockedLimiter[K]) Allow(ctx context.Context, key K, n int) (bool, error) {
+	err := l.locks.Lock(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	allowed, err := l.limiter.Allow(ctx, key, n)
+	return allowed, errors.Join(err, l.locks.Unlock(context.WithoutCancel(ctx), key))
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+// SlidingWindowOptions configures a sliding window limiter.
+type SlidingWindowOptions struct {
+	// Limit is the maximum number of events within any window.
+	Limit int
+	// Window is the duration of the window.
+	Window time.Duration
+	// Clock is used to get the current time, time.Now if nil.
+	Clock Clock
+}
+
+const slidingWindowStateSize = 24
+
+// SlidingWindowState is a state of a single sliding window.
+//
+// The window is approximated by counters of the current and the previous fixed windows,
+// the previous counter is weighted by the part of the previous window which is still in the sliding window.
+// Its binary form is 8 bytes of big-endian start time of the current window in unix nanoseconds
+// followed by 8 bytes of the previous and 8 bytes of the current counters, all big-endian.
+type SlidingWindowState struct {
+	Start    time.Time
+	Previous int64
+	Current  int64
+}
+
+var _ kv.Binary = (*SlidingWindowState)(nil)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s SlidingWindowState) MarshalBinary() ([]byte, error) {
+	data := make([]byte, slidingWindowStateSize)
+	if !s.Start.IsZero() {
+		binary.BigEndian.PutUint64(data[:8], uint64(s.Start.UnixNano()))
+	}
+	binary.BigEndian.PutUint64(data[8:16], uint64(s.Previous))
+	binary.BigEndian.PutUint64(data[16:], uint64(s.Current))
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *SlidingWindowState) UnmarshalBinary(data []byte) error {
+	if len(data) != slidingWindowStateSize {
+		return ErrInvalidState
+	}
+
+	s.Start = time.Time{}
+	if start := int64(binary.BigEndian.Uint64(data[:8])); start != 0 {
+		s.Start = time.Unix(0, start)
+	}
+	s.Previous = int64(binary.BigEndian.Uint64(data[8:16]))
+	s.Current = int64(binary.BigEndian.Uint64(data[16:]))
+	return nil
+}
+
+// Take moves the window to now and counts n events in it, if they fit the limit.
+// It returns the new state and whether the events were counted.
+func (s SlidingWindowState) Take(opts SlidingWindowOptions, n int, now time.Time) (SlidingWindowState, bool) {
+	start := now.Truncate(opts.Window)
+	switch {
+	case s.Start.Equal(start):
+	case s.Start.Add(opts.Window).Equal(start):
+		s = SlidingWindowState{Start: start, Previous: s.Current}
+	case s.Start.Before(start):
+		s = SlidingWindowState{Start: start}
+	default:
+		// the clock went backwards, count the events in the latest window
+	}
+
+	weight := 1 - float64(now.Sub(start))/float64(opts.Window)
+	estimate := float64(s.Previous)*max(weight, 0) + float64(s.Current)
+	if estimate+float64(n) > float64(opts.Limit) {
+		return s, false
+	}
+	s.Current += int64(n)
+	return s, true
+}
+
+// expiresIn returns the time until the events of both counted windows are out of the sliding window.
+func (s SlidingWindowState) expiresIn(opts SlidingWindowOptions, now time.Time) time.Duration {
+	return s.Start.Add(2 * opts.Window).Sub(now)
+}
+
+// NewSlidingWindow creates a sliding window limiter keeping windows in the store.
+// A window expires once its events are out of the sliding window.
+func NewSlidingWindow[K any](store kv.Store[K, SlidingWindowState], opts SlidingWindowOptions) Limiter[K] {
+	return newStoreLimiter(store, opts.Clock, func(s SlidingWindowState, n int, now time.Time) (SlidingWindowState, bool) {
+		return s.Take(opts, n, now)
+	}, func(s SlidingWindowState, now time.Time) time.Duration {
+		return s.expiresIn(opts, now)
+	})
+}
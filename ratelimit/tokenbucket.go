@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+// TokenBucketOptions configures a token bucket limiter.
+type TokenBucketOptions struct {
+	// Rate is the number of tokens added to the bucket per second.
+	Rate float64
+	// Burst is the capacity of the bucket, a new bucket starts full.
+	Burst int
+	// Clock is used to get the current time, time.Now if nil.
+	Clock Clock
+}
+
+const tokenBucketStateSize = 16
+
+// TokenBucketState is a state of a single bucket.
+// Its binary form is 8 bytes of big-endian float64 tokens
+// followed by 8 bytes of big-endian update time in unix nanoseconds.
+type TokenBucketState struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+var _ kv.Binary = (*TokenBucketState)(nil)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s TokenBucketState) MarshalBinary() ([]byte, error) {
+	data := make([]byte, tokenBucketStateSize)
+	binary.BigEndian.PutUint64(data[:8], math.Float64bits(s.Tokens))
+	if !s.Updated.IsZero() {
+		binary.BigEndian.PutUint64(data[8:], uint64(s.Updated.UnixNano()))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *TokenBucketState) UnmarshalBinary(data []byte) error {
+	if len(data) != tokenBucketStateSize {
+		return ErrInvalidState
+	}
+
+	s.Tokens = math.Float64frombits(binary.BigEndian.Uint64(data[:8]))
+	s.Updated = time.Time{}
+	if u := int64(binary.BigEndian.Uint64(data[8:])); u != 0 {
+		s.Updated = time.Unix(0, u)
+	}
+	return nil
+}
+
+// Take refills the bucket up to now and takes n tokens from it, if there are enough.
+// It returns the new state and whether the tokens were taken, a zero state is a full bucket.
+func (s TokenBucketState) Take(opts TokenBucketOptions, n int, now time.Time) (TokenBucketState, bool) {
+	burst := float64(opts.Burst)
+	if s.Updated.IsZero() {
+		s.Tokens = burst
+	} else if elapsed := now.Sub(s.Updated); elapsed > 0 {
+		s.Tokens = min(burst, s.Tokens+elapsed.Seconds()*opts.Rate)
+	}
+	if now.After(s.Updated) {
+		s.Updated = now
+	}
+
+	if s.Tokens < float64(n) {
+		return s, false
+	}
+	s.Tokens -= float64(n)
+	return s, true
+}
+
+// expiresIn returns the time until the bucket is full again, zero if it never refills.
+func (s TokenBucketState) expiresIn(opts TokenBucketOptions, now time.Time) time.Duration {
+	if opts.Rate <= 0 {
+		return 0
+	}
+
+	refill := (float64(opts.Burst) - s.Tokens) / opts.Rate * float64(time.Second)
+	if refill > float64(math.MaxInt64/2) {
+		// refills too slowly to expire
+		return 0
+	}
+	return s.Updated.Add(time.Duration(math.Ceil(refill))).Sub(now)
+}
+
+// NewTokenBucket creates a token bucket limiter keeping buckets in the store.
+// A bucket expires once it is full again.
+func NewTokenBucket[K any](store kv.Store[K, TokenBucketState], opts TokenBucketOptions) Limiter[K] {
+	return newStoreLimiter(store, opts.Clock, func(s TokenBucketState, n int, now time.Time) (TokenBucketState, bool) {
+		return s.Take(opts, n, now)
+	}, func(s TokenBucketState, now time.Time) time.Duration {
+		return s.expiresIn(opts, now)
+	})
+}
@@ -0,0 +1,238 @@
+// Package semaphore implements weighted counting semaphores shared through a key-value store.
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+var (
+	ErrInvalidWeight = errors.New("weight must be positive and not greater than the semaphore size")
+	ErrNotHeld       = errors.New("permit is not held")
+	ErrInvalidPoll   = errors.New("poll interval must be positive and shorter than ttl")
+)
+
+// Options configures a semaphore.
+type Options struct {
+	// Size is the number of permits of every key.
+	Size int64
+	// TTL is the time after which permits of crashed holders and abandoned waiters are reclaimed.
+	// A permit held longer must be renewed with [Semaphore.Renew].
+	TTL time.Duration
+	// PollInterval is the interval of checks while waiting for permits, every check refreshes the waiter,
+	// so it must be shorter than TTL.
+	PollInterval time.Duration
+}
+
+// DefaultOptions are options of a binary semaphore, change Size to allow more holders.
+var DefaultOptions = Options{
+	Size:         1,
+	TTL:          time.Minute,
+	PollInterval: 50 * time.Millisecond,
+}
+
+// Entry is a holder or a waiter of a semaphore.
+type Entry struct {
+	ID        string    `json:"id"`
+	Weight    int64     `json:"weight"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// State is a state of a semaphore key, it is stored in the store as a single value.
+type State struct {
+	Holders []Entry `json:"holders"`
+	// Waiters are served in order, so a heavy waiter is not starved by lighter ones.
+	Waiters []Entry `json:"waiters"`
+}
+
+// Permit is an acquired weight of a semaphore key.
+type Permit struct {
+	Key    string
+	ID     string
+	Weight int64
+}
+
+// Semaphore limits the total weight of concurrent holders per key.
+//
+// States are updated under the lock of the key, so the semaphore is shared by all processes
+// using the same store and locks, e.g. kvolric ones for a cluster.
+type Semaphore struct {
+	store kv.Store[string, State]
+	locks kv.Locks[string]
+	opts  Options
+}
+
+// New creates a semaphore keeping states in the store and serializing their updates with the locks.
+// It returns ErrInvalidPoll if waiters would expire between their checks.
+func New(store kv.Store[string, State], locks kv.Locks[string], opts Options) (*Semaphore, error) {
+	if opts.PollInterval <= 0 || opts.PollInterval >= opts.TTL {
+		return nil, ErrInvalidPoll
+	}
+
+	return &Semaphore{
+		store: store,
+		locks: locks,
+		opts:  opts,
+	}, nil
+}
+
+// Acquire blocks until the weight is acquired for the key or the context is done.
+// Waiters are served in the order of arrival.
+func (s *Semaphore) Acquire(ctx context.Context, key string, weight int64) (Permit, error) {
+	if weight <= 0 || weight > s.opts.Size {
+		return Permit{}, ErrInvalidWeight
+	}
+
+	p := Permit{Key: key, ID: kv.NewLockOwner(), Weight: weight}
+	for {
+		acquired := false
+		err := s.update(ctx, key, func(state *State, now time.Time) {
+			acquired = state.take(p, s.opts.Size, now.Add(s.opts.TTL))
+		})
+		if err != nil {
+			return Permit{}, s.abandon(ctx, p, err)
+		}
+		if acquired {
+			return p, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Permit{}, s.abandon(ctx, p, ctx.Err())
+		case <-time.After(s.opts.PollInterval):
+		}
+	}
+}
+
+// Release returns the permit, it returns ErrNotHeld if the permit expired.
+func (s *Semaphore) Release(ctx context.Context, p Permit) error {
+	held := false
+	err := s.update(ctx, p.Key, func(state *State, now time.Time) {
+		state.Holders = slices.DeleteFunc(state.Holders, func(e Entry) bool {
+			if e.ID == p.ID {
+				held = true
+				return true
+			}
+			return false
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Renew extends the permit for another TTL, it returns ErrNotHeld if the permit expired.
+func (s *Semaphore) Renew(ctx context.Context, p Permit) error {
+	held := false
+	err := s.update(ctx, p.Key, func(state *State, now time.Time) {
+		for i := range state.Holders {
+			if state.Holders[i].ID == p.ID {
+				state.Holders[i].ExpiresAt = now.Add(s.opts.TTL)
+				held = true
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Available returns the weight which is not held for the key.
+func (s *Semaphore) Available(ctx context.Context, key string) (int64, error) {
+	state, err := s.store.Get(ctx, key)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return s.opts.Size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	state.expire(time.Now())
+	return s.opts.Size - state.held(), nil
+}
+
+// abandon removes the permit from the waiters after a failed acquisition.
+func (s *Semaphore) abandon(ctx context.Context, p Permit, err error) error {
+	removeErr := s.update(context.WithoutCancel(ctx), p.Key, func(state *State, now time.Time) {
+		state.Waiters = slices.DeleteFunc(state.Waiters, func(e Entry) bool {
+			return e.ID == p.ID
+		})
+	})
+	return errors.Join(err, removeErr)
+}
+
+// update applies the function to the state of the key under its lock, expired entries are removed beforehand.
+func (s *Semaphore) update(ctx context.Context, key string, f func(state *State, now time.Time)) (err error) {
+	err = s.locks.Lock(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, s.locks.Unlock(context.WithoutCancel(ctx), key))
+	}()
+
+	state, err := s.store.Get(ctx, key)
+	if err != nil && !errors.Is(err, kv.ErrKeyNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	state.expire(now)
+	f(&state, now)
+
+	if len(state.Holders) == 0 && len(state.Waiters) == 0 {
+		return s.store.Delete(ctx, key)
+	}
+	return s.store.Set(ctx, key, state)
+}
+
+// take acquires the permit if it fits and all preceding waiters are served,
+// otherwise it enqueues the permit or refreshes its waiter expiration.
+func (state *State) take(p Permit, size int64, expiresAt time.Time) bool {
+	i := slices.IndexFunc(state.Waiters, func(e Entry) bool {
+		return e.ID == p.ID
+	})
+	first := i == 0 || (i < 0 && len(state.Waiters) == 0)
+
+	if first && state.held()+p.Weight <= size {
+		if i == 0 {
+			state.Waiters = state.Waiters[1:]
+		}
+		state.Holders = append(state.Holders, Entry{ID: p.ID, Weight: p.Weight, ExpiresAt: expiresAt})
+		return true
+	}
+
+	if i < 0 {
+		state.Waiters = append(state.Waiters, Entry{ID: p.ID, Weight: p.Weight, ExpiresAt: expiresAt})
+	} else {
+		state.Waiters[i].ExpiresAt = expiresAt
+	}
+	return false
+}
+
+func (state *State) held() int64 {
+	var held int64
+	for _, e := range state.Holders {
+		held += e.Weight
+	}
+	return held
+}
+
+func (state *State) expire(now time.Time) {
+	expired := func(e Entry) bool {
+		return !now.Before(e.ExpiresAt)
+	}
+	state.Holders = slices.DeleteFunc(state.Holders, expired)
+	state.Waiters = slices.DeleteFunc(state.Waiters, expired)
+}
@@ -0,0 +1,119 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+var ErrInvalidSequenceKey = errors.New("invalid sequence key")
+
+// Sequence generates monotonically increasing positive IDs.
+// Implementations lease ranges of IDs from the store to avoid a write per ID,
+// so IDs leased by a crashed or not released sequence are skipped, leaving gaps.
+type Sequence interface {
+	// Next returns the next ID in the sequence.
+	Next(ctx context.Context) (uint64, error)
+
+	// Release returns the unused part of the leased range to the store, if no other lease was taken after it.
+	// It should be called before closing the store. It is valid to use the sequence after it was released,
+	// causing a new lease.
+	Release(ctx context.Context) error
+}
+
+// SequenceKey appends the ID to the prefix in big-endian form, so the keys are ordered by ID
+// in ordered stores, see [StoreOrdered].
+func SequenceKey[K Bytes](prefix K, id uint64) K {
+	return K(binary.BigEndian.AppendUint64([]byte(prefix), id))
+}
+
+// SequenceID parses the ID from a key created by [SequenceKey].
+func SequenceID[K Bytes](k K) (uint64, error) {
+	if len(k) < 8 {
+		return 0, ErrInvalidSequenceKey
+	}
+	return binary.BigEndian.Uint64([]byte(k[len(k)-8:])), nil
+}
+
+// NewStoreSequence creates a [Sequence] on top of any store, leasing bandwidth IDs at once
+// by incrementing the value of the key with [Store.Edit].
+//
+// Store doesn't provide conditional creation of a key, so concurrent creation of the same sequence
+// from different processes may lease the same range, prefer backend native sequences for shared stores.
+func NewStoreSequence[K any](s Store[K, uint64], key K, bandwidth uint64) Sequence {
+	return &storeSequence[K]{
+		store:     s,
+		key:       key,
+		bandwidth: max(bandwidth, 1),
+		next:      1,
+	}
+}
+
+type storeSequence[K any] struct {
+	mu        sync.Mutex
+	store     Store[K, uint64]
+	key       K
+	bandwidth uint64
+
+	// next is the next ID to return, leased is the last leased ID
+	next, leased uint64
+}
+
+// Next implements Sequence.
+func (s *storeSequence[K]) Next(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next > s.leased {
+		if err := s.updateLease(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	id := s.next
+	s.next++
+	return id, nil
+}
+
+func (s *storeSequence[K]) updateLease(ctx context.Context) error {
+	var leased uint64
+	err := s.store.Edit(ctx, s.key, func(ctx context.Context, v uint64) (uint64, error) {
+		leased = v + s.bandwidth
+		return leased, nil
+	})
+	if errors.Is(err, ErrKeyNotFound) {
+		leased = s.bandwidth
+		err = s.store.Set(ctx, s.key, leased)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.next = leased - s.bandwidth + 1
+	s.leased = leased
+	return nil
+}
+
+// Release implements Sequence.
+func (s *storeSequence[K]) Release(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next > s.leased {
+		return nil
+	}
+
+	err := s.store.Edit(ctx, s.key, func(ctx context.Context, v uint64) (uint64, error) {
+		if v == s.leased {
+			return s.next - 1, nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.leased = s.next - 1
+	return nil
+}
@@ -0,0 +1,65 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func testHasCount(t *testing.T, ctx context.Context, store kv.Store[string, string]) {
+	require := require.New(t)
+
+	ok, err := kv.Has(ctx, store, "a1")
+	require.NoError(err)
+	require.False(ok)
+
+	n, err := kv.Count(ctx, store, "")
+	require.NoError(err)
+	require.Equal(0, n)
+
+	for _, k := range []string{"a1", "a2", "b1"} {
+		err := store.Set(ctx, k, "value")
+		require.NoError(err)
+	}
+
+	ok, err = kv.Has(ctx, store, "a1")
+	require.NoError(err)
+	require.True(ok)
+
+	ok, err = kv.Has(ctx, store, "a")
+	require.NoError(err)
+	require.False(ok)
+
+	n, err = kv.Count(ctx, store, "")
+	require.NoError(err)
+	require.Equal(3, n)
+
+	n, err = kv.Count(ctx, store, "a")
+	require.NoError(err)
+	require.Equal(2, n)
+
+	pm := kv.PrefixBytes(store, "a")
+	ok, err = kv.Has(ctx, pm, "2")
+	require.NoError(err)
+	require.True(ok)
+
+	n, err = kv.Count(ctx, pm, "")
+	require.NoError(err)
+	require.Equal(2, n)
+
+	err = store.Delete(ctx, "a1")
+	require.NoError(err)
+
+	ok, err = kv.Has(ctx, store, "a1")
+	require.NoError(err)
+	require.False(ok)
+
+	n, err = kv.Count(ctx, store, "a")
+	require.NoError(err)
+	require.Equal(1, n)
+
+	err = store.Close(ctx)
+	require.NoError(err)
+}
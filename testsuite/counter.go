@@ -0,0 +1,129 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+type CounterConstructor[K any] func(ttl time.Duration) (kv.Counter[K], error)
+
+func GoldenCounter(t *testing.T, newCounter CounterConstructor[string]) {
+	ctx := context.Background()
+	t.Run("Incr Decr", func(t *testing.T) {
+		require := require.New(t)
+		counter, err := newCounter(0)
+		require.NoError(err)
+
+		testCounterIncrDecr(t, ctx, counter)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		require := require.New(t)
+		counter, err := newCounter(0)
+		require.NoError(err)
+
+		testCounterConcurrent(t, ctx, counter)
+	})
+	t.Run("TTL", func(t *testing.T) {
+		require := require.New(t)
+		counter, err := newCounter(500 * time.Millisecond)
+		require.NoError(err)
+
+		testCounterTTL(t, ctx, counter)
+	})
+}
+
+func testCounterIncrDecr(t *testing.T, ctx context.Context, counter kv.Counter[string]) {
+	require := require.New(t)
+
+	v, err := counter.GetCounter(ctx, "counter")
+	require.NoError(err)
+	require.EqualValues(0, v)
+
+	v, err = counter.Incr(ctx, "counter", 5)
+	require.NoError(err)
+	require.EqualValues(5, v)
+
+	v, err = counter.Decr(ctx, "counter", 2)
+	require.NoError(err)
+	require.EqualValues(3, v)
+
+	v, err = counter.Decr(ctx, "other", 2)
+	require.NoError(err)
+	require.EqualValues(-2, v)
+
+	v, err = counter.GetCounter(ctx, "counter")
+	require.NoError(err)
+	require.EqualValues(3, v)
+}
+
+func testCounterConcurrent(t *testing.T, ctx context.Context, counter kv.Counter[string]) {
+	require := require.New(t)
+
+	const workers, increments = 8, 10
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, workers*increments)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range increments {
+				_, err := counter.Incr(ctx, "counter", 1)
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+
+	v, err := counter.GetCounter(ctx, "counter")
+	require.NoError(err)
+	require.EqualValues(workers*increments, v)
+}
+
+func testCounterTTL(t *testing.T, ctx context.Context, counter kv.Counter[string]) {
+	require := require.New(t)
+
+	v, err := counter.Incr(ctx, "counter", 1)
+	require.NoError(err)
+	require.EqualValues(1, v)
+
+	v, err = counter.Incr(ctx, "counter", 1)
+	require.NoError(err)
+	require.EqualValues(2, v)
+
+	// a counter returning to a value of its first increment keeps the original expiration
+	_, err = counter.Incr(ctx, "returning", 1)
+	require.NoError(err)
+	_, err = counter.Decr(ctx, "returning", 1)
+	require.NoError(err)
+
+	time.Sleep(400 * time.Millisecond)
+
+	v, err = counter.Incr(ctx, "returning", 1)
+	require.NoError(err)
+	require.EqualValues(1, v)
+
+	time.Sleep(300 * time.Millisecond)
+
+	v, err = counter.GetCounter(ctx, "counter")
+	require.NoError(err)
+	require.EqualValues(0, v)
+
+	v, err = counter.GetCounter(ctx, "returning")
+	require.NoError(err)
+	require.EqualValues(0, v)
+
+	v, err = counter.Incr(ctx, "counter", 1)
+	require.NoError(err)
+	require.EqualValues(1, v)
+}
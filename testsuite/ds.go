@@ -0,0 +1,288 @@
+package testsuite
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/ds"
+	"github.com/stretchr/testify/require"
+)
+
+func GoldenDataStructures(t *testing.T, newStore StoreConstructor[string, []byte]) {
+	ctx := context.Background()
+	t.Run("Hash", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testHash(t, ctx, store)
+	})
+	t.Run("Set", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testSet(t, ctx, store)
+	})
+	t.Run("List", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testList(t, ctx, store)
+	})
+	t.Run("Namespaces", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testNamespaces(t, ctx, store)
+	})
+	t.Run("List Concurrent", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testListConcurrent(t, ctx, store)
+	})
+}
+
+func testHash(t *testing.T, ctx context.Context, store kv.Store[string, []byte]) {
+	require := require.New(t)
+
+	h := ds.NewHash(store, "h", kv.CodecJSON[int]{})
+	other := ds.NewHash(store, "h2", kv.CodecJSON[int]{})
+
+	require.NoError(h.HSet(ctx, "a", 1))
+	require.NoError(h.HSet(ctx, "b", 2))
+	require.NoError(h.HSet(ctx, "a", 3))
+	require.NoError(other.HSet(ctx, "c", 4))
+
+	v, err := h.HGet(ctx, "a")
+	require.NoError(err)
+	require.Equal(3, v)
+
+	_, err = h.HGet(ctx, "c")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+
+	ok, err := h.HExists(ctx, "b")
+	require.NoError(err)
+	require.True(ok)
+
+	n, err := h.HLen(ctx)
+	require.NoError(err)
+	require.Equal(2, n)
+
+	fields := map[string]int{}
+	require.NoError(h.HRange(ctx, func(k string, v int) error {
+		fields[k] = v
+		return nil
+	}))
+	require.Equal(map[string]int{"a": 3, "b": 2}, fields)
+
+	keys := []string{}
+	require.NoError(h.HKeys(ctx, func(k string) error {
+		keys = append(keys, k)
+		return nil
+	}))
+	slices.Sort(keys)
+	require.Equal([]string{"a", "b"}, keys)
+
+	require.NoError(h.HDel(ctx, "a"))
+	require.NoError(h.HDel(ctx, "missing"))
+	ok, err = h.HExists(ctx, "a")
+	require.NoError(err)
+	require.False(ok)
+
+	n, err = other.HLen(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+}
+
+func testSet(t *testing.T, ctx context.Context, store kv.Store[string, []byte]) {
+	require := require.New(t)
+
+	s := ds.NewSet(store, "s")
+	other := ds.NewSet(store, "s2")
+
+	require.NoError(s.SAdd(ctx, "a", "b", "c", "a"))
+	require.NoError(other.SAdd(ctx, "d"))
+
+	n, err := s.SCard(ctx)
+	require.NoError(err)
+	require.Equal(3, n)
+
+	ok, err := s.SIsMember(ctx, "b")
+	require.NoError(err)
+	require.True(ok)
+
+	ok, err = s.SIsMember(ctx, "d")
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(s.SRem(ctx, "b", "missing"))
+
+	members := []string{}
+	require.NoError(s.SMembers(ctx, func(k string) error {
+		members = append(members, k)
+		return nil
+	}))
+	slices.Sort(members)
+	require.Equal([]string{"a", "c"}, members)
+}
+
+func testList(t *testing.T, ctx context.Context, store kv.Store[string, []byte]) {
+	require := require.New(t)
+
+	l := ds.NewList(store, "l", kv.CodecJSON[string]{})
+
+	_, err := l.LPop(ctx)
+	require.ErrorIs(err, ds.ErrEmpty)
+
+	require.NoError(l.RPush(ctx, "c", "d"))
+	require.NoError(l.LPush(ctx, "b", "a"))
+
+	n, err := l.LLen(ctx)
+	require.NoError(err)
+	require.Equal(4, n)
+
+	v, err := l.LIndex(ctx, 0)
+	require.NoError(err)
+	require.Equal("a", v)
+
+	v, err = l.LIndex(ctx, -1)
+	require.NoError(err)
+	require.Equal("d", v)
+
+	_, err = l.LIndex(ctx, 4)
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+
+	values := []string{}
+	require.NoError(l.LRange(ctx, 1, -1, func(i int, v string) error {
+		values = append(values, v)
+		return nil
+	}))
+	require.Equal([]string{"b", "c", "d"}, values)
+
+	values = []string{}
+	require.NoError(l.LRange(ctx, -100, 100, func(i int, v string) error {
+		values = append(values, v)
+		return nil
+	}))
+	require.Equal([]string{"a", "b", "c", "d"}, values)
+
+	v, err = l.LPop(ctx)
+	require.NoError(err)
+	require.Equal("a", v)
+
+	v, err = l.RPop(ctx)
+	require.NoError(err)
+	require.Equal("d", v)
+
+	v, err = l.RPop(ctx)
+	require.NoError(err)
+	require.Equal("c", v)
+
+	v, err = l.RPop(ctx)
+	require.NoError(err)
+	require.Equal("b", v)
+
+	_, err = l.RPop(ctx)
+	require.ErrorIs(err, ds.ErrEmpty)
+
+	n, err = l.LLen(ctx)
+	require.NoError(err)
+	require.Equal(0, n)
+}
+
+func testListConcurrent(t *testing.T, ctx context.Context, store kv.Store[string, []byte]) {
+	require := require.New(t)
+
+	l := ds.NewList(store, "l", kv.CodecJSON[int]{})
+
+	const workers, perWorker = 4, 25
+	errs := make(chan error, workers*perWorker)
+	wg := sync.WaitGroup{}
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				errs <- l.RPush(ctx, w*perWorker+i)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(err)
+	}
+
+	n, err := l.LLen(ctx)
+	require.NoError(err)
+	require.Equal(workers*perWorker, n)
+
+	seen := map[int]bool{}
+	for range workers * perWorker {
+		v, err := l.LPop(ctx)
+		require.NoError(err)
+		require.False(seen[v])
+		seen[v] = true
+	}
+}
+
+func testNamespaces(t *testing.T, ctx context.Context, store kv.Store[string, []byte]) {
+	require := require.New(t)
+
+	// containers of different kinds with the same name and names prefixed by other names are independent
+	h := ds.NewHash(store, "a", kv.CodecJSON[string]{})
+	nested := ds.NewHash(store, "a/b", kv.CodecJSON[string]{})
+	listLike := ds.NewHash(store, "a/e", kv.CodecJSON[string]{})
+	s := ds.NewSet(store, "a")
+	l := ds.NewList(store, "a", kv.CodecJSON[string]{})
+
+	require.NoError(h.HSet(ctx, "field", "h"))
+	require.NoError(nested.HSet(ctx, "field", "nested"))
+	require.NoError(nested.HSet(ctx, "b/field", "nested"))
+	require.NoError(listLike.HSet(ctx, "meta", "listLike"))
+	require.NoError(s.SAdd(ctx, "field", "member"))
+	require.NoError(l.RPush(ctx, "x", "y"))
+
+	fields := map[string]string{}
+	require.NoError(h.HRange(ctx, func(k, v string) error {
+		fields[k] = v
+		return nil
+	}))
+	require.Equal(map[string]string{"field": "h"}, fields)
+
+	n, err := h.HLen(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+
+	n, err = nested.HLen(ctx)
+	require.NoError(err)
+	require.Equal(2, n)
+
+	n, err = listLike.HLen(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+
+	n, err = s.SCard(ctx)
+	require.NoError(err)
+	require.Equal(2, n)
+
+	n, err = l.LLen(ctx)
+	require.NoError(err)
+	require.Equal(2, n)
+
+	v, err := h.HGet(ctx, "field")
+	require.NoError(err)
+	require.Equal("h", v)
+
+	v, err = l.LIndex(ctx, 0)
+	require.NoError(err)
+	require.Equal("x", v)
+}
@@ -0,0 +1,383 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/election"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldenElection runs elections over the store with fake lease locks, which lose leases only when told to,
+// and a fake clock expiring the leader ID, so the outcome doesn't depend on timing.
+func GoldenElection(t *testing.T, newStore StoreConstructor[string, string]) {
+	ctx := context.Background()
+	opts := election.Options{
+		// the leader ID is never refreshed during a test, it expires only when the fake clock is advanced
+		TTL:          time.Hour,
+		PollInterval: time.Millisecond,
+	}
+
+	newElections := func(t *testing.T, ids ...string) ([]*election.Election, *fakeLeaseLocks, *fakeClock) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		locks := newFakeLeaseLocks()
+		clock := newFakeClock()
+		ttlStore := &fakeTTLStore{Store: store, clock: clock, expires: map[string]time.Time{}}
+
+		elections := []*election.Election{}
+		for _, id := range ids {
+			elections = append(elections, election.New(locks, ttlStore, "leader", id, opts))
+		}
+		return elections, locks, clock
+	}
+
+	t.Run("Campaign", func(t *testing.T) {
+		elections, locks, _ := newElections(t, "a", "b")
+		testElectionCampaign(t, ctx, elections, locks)
+	})
+	t.Run("Crash", func(t *testing.T) {
+		elections, locks, clock := newElections(t, "a", "b")
+		testElectionCrash(t, ctx, elections, locks, clock, opts.TTL)
+	})
+	t.Run("Observe", func(t *testing.T) {
+		elections, _, _ := newElections(t, "a", "b")
+		testElectionObserve(t, ctx, elections)
+	})
+	t.Run("Run", func(t *testing.T) {
+		elections, _, _ := newElections(t, "a", "b", "c")
+		testElectionRun(t, ctx, elections)
+	})
+}
+
+func testElectionCampaign(t *testing.T, ctx context.Context, elections []*election.Election, locks *fakeLeaseLocks) {
+	require := require.New(t)
+	a, b := elections[0], elections[1]
+
+	_, err := a.Leader(ctx)
+	require.ErrorIs(err, election.ErrNoLeader)
+	require.ErrorIs(a.Resign(ctx), election.ErrNotLeader)
+
+	lost, err := a.Campaign(ctx)
+	require.NoError(err)
+	require.True(a.IsLeader())
+
+	// campaigning again keeps the leadership
+	again, err := a.Campaign(ctx)
+	require.NoError(err)
+	require.Equal(lost, again)
+
+	leader, err := b.Leader(ctx)
+	require.NoError(err)
+	require.Equal("a", leader)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancelled := make(chan error)
+	go func() {
+		_, err := b.Campaign(cancelCtx)
+		cancelled <- err
+	}()
+	locks.awaitWaiters(1)
+	cancel()
+	require.ErrorIs(<-cancelled, context.Canceled)
+	require.False(b.IsLeader())
+
+	elected := make(chan error)
+	go func() {
+		_, err := b.Campaign(ctx)
+		elected <- err
+	}()
+	locks.awaitWaiters(1)
+
+	require.NoError(a.Resign(ctx))
+	<-lost
+	require.False(a.IsLeader())
+
+	require.NoError(<-elected)
+	require.True(b.IsLeader())
+	leader, err = a.Leader(ctx)
+	require.NoError(err)
+	require.Equal("b", leader)
+
+	require.NoError(b.Resign(ctx))
+	require.Zero(locks.unreleased())
+}
+
+func testElectionCrash(t *testing.T, ctx context.Context, elections []*election.Election, locks *fakeLeaseLocks, clock *fakeClock, ttl time.Duration) {
+	require := require.New(t)
+	a, b := elections[0], elections[1]
+
+	lost, err := a.Campaign(ctx)
+	require.NoError(err)
+
+	// the leader stopped renewing its lease
+	locks.expire()
+	<-lost
+	require.False(a.IsLeader())
+
+	leader, err := b.Leader(ctx)
+	require.NoError(err)
+	require.Equal("a", leader)
+
+	// the ID of the crashed leader expires along with its lease
+	clock.Advance(ttl)
+	_, err = b.Leader(ctx)
+	require.ErrorIs(err, election.ErrNoLeader)
+
+	// campaigning again releases the lost lease
+	_, err = a.Campaign(ctx)
+	require.NoError(err)
+	require.True(a.IsLeader())
+	require.Equal(1, locks.unreleased())
+
+	require.NoError(a.Resign(ctx))
+	require.Zero(locks.unreleased())
+}
+
+func testElectionObserve(t *testing.T, ctx context.Context, elections []*election.Election) {
+	require := require.New(t)
+	a, b := elections[0], elections[1]
+
+	observeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	leaders := b.Observe(observeCtx)
+	require.Equal("", <-leaders)
+
+	_, err := a.Campaign(ctx)
+	require.NoError(err)
+	require.Equal("a", <-leaders)
+
+	require.NoError(a.Resign(ctx))
+	require.Equal("", <-leaders)
+
+	_, err = b.Campaign(ctx)
+	require.NoError(err)
+	require.Equal("b", <-leaders)
+	require.NoError(b.Resign(ctx))
+
+	cancel()
+	for range leaders {
+	}
+}
+
+func testElectionRun(t *testing.T, ctx context.Context, elections []*election.Election) {
+	require := require.New(t)
+
+	const terms = 6
+	var leading, violations, served atomic.Int32
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(elections))
+	wg := sync.WaitGroup{}
+	for _, e := range elections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- e.Run(runCtx, func(ctx context.Context) {
+				if leading.Add(1) > 1 {
+					violations.Add(1)
+				}
+				defer leading.Add(-1)
+
+				// serve a term and step down to let others lead
+				if served.Add(1) >= terms {
+					cancel()
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.ErrorIs(err, context.Canceled)
+	}
+	require.Zero(violations.Load())
+	require.GreaterOrEqual(served.Load(), int32(terms))
+}
+
+// fakeLeaseLocks are lease locks which never expire on their own, a lease is lost only when it is released or expired.
+type fakeLeaseLocks struct {
+	mu     sync.Mutex
+	held   map[string]*fakeLease
+	leases map[*fakeLease]struct{}
+	token  uint64
+	// waiters is the number of Lock calls waiting for a held lease
+	waiters int
+	// changed is closed and replaced on every change
+	changed chan struct{}
+}
+
+func newFakeLeaseLocks() *fakeLeaseLocks {
+	return &fakeLeaseLocks{
+		held:    map[string]*fakeLease{},
+		leases:  map[*fakeLease]struct{}{},
+		changed: make(chan struct{}),
+	}
+}
+
+var _ kv.LeaseLocks[string] = (*fakeLeaseLocks)(nil)
+
+func (l *fakeLeaseLocks) Lock(ctx context.Context, key string, ttl time.Duration) (kv.Lease, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	waiting := false
+	defer func() {
+		if waiting {
+			l.waiters--
+			l.notify()
+		}
+	}()
+	for l.held[key] != nil {
+		if !waiting {
+			waiting = true
+			l.waiters++
+			l.notify()
+		}
+
+		changed := l.changed
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			return nil, ctx.Err()
+		case <-changed:
+		}
+		l.mu.Lock()
+	}
+
+	l.token++
+	lease := &fakeLease{locks: l, key: key, token: l.token, lost: make(chan struct{})}
+	l.held[key] = lease
+	l.leases[lease] = struct{}{}
+	l.notify()
+	return lease, nil
+}
+
+func (l *fakeLeaseLocks) Close(ctx context.Context) error {
+	return nil
+}
+
+// expire loses all held leases, as if their holders stopped renewing them.
+func (l *fakeLeaseLocks) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, lease := range l.held {
+		lease.isLost = true
+		close(lease.lost)
+		delete(l.held, key)
+	}
+	l.notify()
+}
+
+// awaitWaiters blocks until n Lock calls wait for held leases.
+func (l *fakeLeaseLocks) awaitWaiters(n int) {
+	l.mu.Lock()
+	for l.waiters < n {
+		changed := l.changed
+		l.mu.Unlock()
+		<-changed
+		l.mu.Lock()
+	}
+	l.mu.Unlock()
+}
+
+// unreleased returns the number of leases which were not released, lost or not.
+func (l *fakeLeaseLocks) unreleased() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.leases)
+}
+
+// notify must be called with the mutex held.
+func (l *fakeLeaseLocks) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+type fakeLease struct {
+	locks  *fakeLeaseLocks
+	key    string
+	token  uint64
+	lost   chan struct{}
+	isLost bool
+}
+
+func (l *fakeLease) Token() uint64 {
+	return l.token
+}
+
+func (l *fakeLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *fakeLease) Release(ctx context.Context) error {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	delete(l.locks.leases, l)
+	if l.isLost {
+		return kv.ErrLeaseLost
+	}
+	if l.locks.held[l.key] == l {
+		delete(l.locks.held, l.key)
+		close(l.lost)
+		l.isLost = true
+		l.locks.notify()
+		return nil
+	}
+	return kv.ErrLeaseLost
+}
+
+// fakeTTLStore expires values written with SetWithTTL by the fake clock.
+type fakeTTLStore struct {
+	kv.Store[string, string]
+	clock *fakeClock
+
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+var _ kv.StoreTTL[string, string] = (*fakeTTLStore)(nil)
+
+func (s *fakeTTLStore) Get(ctx context.Context, k string) (string, error) {
+	s.mu.Lock()
+	exp, ok := s.expires[k]
+	s.mu.Unlock()
+	if ok && !s.clock.Now().Before(exp) {
+		return "", kv.ErrKeyNotFound
+	}
+	return s.Store.Get(ctx, k)
+}
+
+func (s *fakeTTLStore) Set(ctx context.Context, k string, v string) error {
+	return s.SetWithTTL(ctx, k, v, 0)
+}
+
+func (s *fakeTTLStore) SetWithTTL(ctx context.Context, k string, v string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.expires, k)
+	if ttl > 0 {
+		s.expires[k] = s.clock.Now().Add(ttl)
+	}
+	return s.Store.Set(ctx, k, v)
+}
+
+func (s *fakeTTLStore) Delete(ctx context.Context, k string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.expires, k)
+	return s.Store.Delete(ctx, k)
+}
@@ -12,6 +12,9 @@ func FuzzPrefixBytes(t *testing.F, newKV StoreConstructor[string, string]) {
 	t.Add("prefix-", "123", "456")
 	t.Add("prefix_", "abc", "xyz")
 	t.Add(string("0"), string("\xff"), string("0"))
+	t.Add("dir/", "sub/key", "value")
+	t.Add("/", "/", "")
+	t.Add("\xff/", "\xff/\xff", "value")
 
 	t.Fuzz(func(t *testing.T, prefix, key, value string) {
 		store, err := newKV()
@@ -7,11 +7,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type testObject struct {
+// TestObject is the value type of GoldenObjects, for constructors which can't be passed as generic functions.
+type TestObject struct {
 	I int
 }
 
-func GoldenObjects(t *testing.T, newKV StoreConstructor[string, testObject]) {
+func GoldenObjects(t *testing.T, newKV StoreConstructor[string, TestObject]) {
 	ctx := context.Background()
 	t.Run("Set Get", func(t *testing.T) {
 		t.Parallel()
@@ -19,6 +20,6 @@ func GoldenObjects(t *testing.T, newKV StoreConstructor[string, testObject]) {
 		store, err := newKV()
 		require.NoError(err)
 
-		testSetGet(t, ctx, store, "key", testObject{I: 42})
+		testSetGet(t, ctx, store, "key", TestObject{I: 42})
 	})
 }
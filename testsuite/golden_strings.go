@@ -33,6 +33,34 @@ func GoldenStrings(t *testing.T, newKV StoreConstructor[string, string]) {
 
 		testPrefixBytes(t, ctx, store, "prefix", "key", "value")
 	})
+	t.Run("Range Keys", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newKV()
+		require.NoError(err)
+
+		testRangeKeys(t, ctx, store)
+	})
+	t.Run("Has Count", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newKV()
+		require.NoError(err)
+
+		testHasCount(t, ctx, store)
+	})
+	t.Run("Range Ordered", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newKV()
+		require.NoError(err)
+
+		testRangeOrdered(t, ctx, store)
+	})
+	t.Run("Range Page", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newKV()
+		require.NoError(err)
+
+		testRangePage(t, ctx, store)
+	})
 }
 
 func testRange(t *testing.T, ctx context.Context, store kv.Store[string, string]) {
@@ -0,0 +1,45 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func testRangeKeys(t *testing.T, ctx context.Context, store kv.Store[string, string]) {
+	require := require.New(t)
+
+	for _, k := range []string{"a1", "a2", "b1"} {
+		err := store.Set(ctx, k, "value")
+		require.NoError(err)
+	}
+
+	keys := map[string]bool{}
+	err := kv.RangeKeys(ctx, store, func(k string) error {
+		keys[k] = true
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(map[string]bool{"a1": true, "a2": true, "b1": true}, keys)
+
+	keys = map[string]bool{}
+	err = kv.RangeKeysWithPrefix(ctx, store, "a", func(k string) error {
+		keys[k] = true
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(map[string]bool{"a1": true, "a2": true}, keys)
+
+	keys = map[string]bool{}
+	err = kv.RangeKeys(ctx, kv.PrefixBytes(store, "a"), func(k string) error {
+		keys[k] = true
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(map[string]bool{"1": true, "2": true}, keys)
+
+	err = store.Close(ctx)
+	require.NoError(err)
+}
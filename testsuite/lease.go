@@ -0,0 +1,135 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+type LeaseLocksConstructor[K any] func() (kv.LeaseLocks[K], error)
+
+func GoldenLeaseLocks(t *testing.T, newLocks LeaseLocksConstructor[string]) {
+	ctx := context.Background()
+	t.Run("Exclusive", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLeaseExclusive(t, ctx, locks)
+	})
+	t.Run("Renewal", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLeaseRenewal(t, ctx, locks)
+	})
+	t.Run("Tiny TTL", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLeaseTinyTTL(t, ctx, locks)
+	})
+	t.Run("Close", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLeaseClose(t, ctx, locks)
+	})
+}
+
+func testLeaseExclusive(t *testing.T, ctx context.Context, locks kv.LeaseLocks[string]) {
+	require := require.New(t)
+
+	_, err := locks.Lock(ctx, "key", 0)
+	require.Error(err)
+
+	first, err := locks.Lock(ctx, "key", time.Second)
+	require.NoError(err)
+
+	other, err := locks.Lock(ctx, "other", time.Second)
+	require.NoError(err)
+	require.NoError(other.Release(ctx))
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = locks.Lock(waitCtx, "key", time.Second)
+	cancel()
+	require.Error(err)
+
+	acquired := make(chan kv.Lease)
+	go func() {
+		lease, err := locks.Lock(ctx, "key", time.Second)
+		if err == nil {
+			acquired <- lease
+		}
+		close(acquired)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(first.Release(ctx))
+	select {
+	case <-first.Lost():
+	default:
+		require.Fail("lost channel must be closed after release")
+	}
+
+	second, ok := <-acquired
+	require.True(ok)
+	require.Greater(second.Token(), first.Token())
+
+	require.NoError(second.Release(ctx))
+}
+
+func testLeaseRenewal(t *testing.T, ctx context.Context, locks kv.LeaseLocks[string]) {
+	require := require.New(t)
+
+	lease, err := locks.Lock(ctx, "key", 300*time.Millisecond)
+	require.NoError(err)
+
+	// the lease outlives its ttl
+	time.Sleep(time.Second)
+	select {
+	case <-lease.Lost():
+		require.Fail("lease must be renewed")
+	default:
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = locks.Lock(waitCtx, "key", time.Second)
+	cancel()
+	require.Error(err)
+
+	require.NoError(lease.Release(ctx))
+}
+
+func testLeaseClose(t *testing.T, ctx context.Context, locks kv.LeaseLocks[string]) {
+	require := require.New(t)
+
+	lease, err := locks.Lock(ctx, "key", time.Second)
+	require.NoError(err)
+
+	require.NoError(locks.Close(ctx))
+	<-lease.Lost()
+}
+
+func testLeaseTinyTTL(t *testing.T, ctx context.Context, locks kv.LeaseLocks[string]) {
+	require := require.New(t)
+
+	// a lease shorter than its renewal can only be lost
+	lease, err := locks.Lock(ctx, "key", time.Nanosecond)
+	require.NoError(err)
+
+	select {
+	case <-lease.Lost():
+	case <-time.After(5 * time.Second):
+		require.Fail("lease with a tiny ttl is not lost")
+	}
+	require.ErrorIs(lease.Release(ctx), kv.ErrLeaseLost)
+
+	require.NoError(locks.Close(ctx))
+}
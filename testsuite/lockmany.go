@@ -0,0 +1,135 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func GoldenLockMany(t *testing.T, newLocks LocksConstructor[string]) {
+	ctx := context.Background()
+	t.Run("Lock Many", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLockMany(t, ctx, locks)
+	})
+	t.Run("Lock Many Cancel", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLockManyCancel(t, ctx, locks)
+	})
+	t.Run("Lock Many Contention", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testLockManyContention(t, ctx, locks)
+	})
+}
+
+func testLockMany(t *testing.T, ctx context.Context, locks kv.Locks[string]) {
+	require := require.New(t)
+
+	unlock, err := kv.LockMany(ctx, locks, "c", "a", "b", "a")
+	require.NoError(err)
+
+	for _, k := range []string{"a", "b", "c"} {
+		ok, err := locks.TryLock(ctx, k)
+		require.NoError(err)
+		require.False(ok, k)
+	}
+
+	require.NoError(unlock(ctx))
+	require.NoError(unlock(ctx))
+
+	for _, k := range []string{"a", "b", "c"} {
+		ok, err := locks.TryLock(ctx, k)
+		require.NoError(err)
+		require.True(ok, k)
+		require.NoError(locks.Unlock(ctx, k))
+	}
+}
+
+func testLockManyCancel(t *testing.T, ctx context.Context, locks kv.Locks[string]) {
+	require := require.New(t)
+
+	// "b" is held by another goroutine
+	locked := make(chan error)
+	go func() {
+		locked <- locks.Lock(ctx, "b")
+	}()
+	require.NoError(<-locked)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err := kv.LockMany(timeoutCtx, locks, "a", "b", "c")
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	// "a" was released after the failure
+	ok, err := locks.TryLock(ctx, "a")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(locks.Unlock(ctx, "a"))
+	require.NoError(locks.Unlock(ctx, "b"))
+}
+
+func testLockManyContention(t *testing.T, ctx context.Context, locks kv.Locks[string]) {
+	require := require.New(t)
+
+	// transfers between accounts in opposite directions
+	pairs := [][]string{{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "a"}}
+	var violations atomic.Int32
+	holders := map[string]*atomic.Int32{"a": {}, "b": {}, "c": {}}
+
+	errs := make(chan error, len(pairs)*10)
+	wg := sync.WaitGroup{}
+	for _, pair := range pairs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				unlock, err := kv.LockMany(ctx, locks, pair...)
+				if err != nil {
+					errs <- err
+					return
+				}
+				for _, k := range pair {
+					if holders[k].Add(1) > 1 {
+						violations.Add(1)
+					}
+				}
+				time.Sleep(time.Millisecond)
+				for _, k := range pair {
+					holders[k].Add(-1)
+				}
+				errs <- unlock(ctx)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		require.Fail("lock many deadlocked")
+	}
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	require.Zero(violations.Load())
+}
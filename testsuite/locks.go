@@ -2,7 +2,10 @@ package testsuite
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/royalcat/kv"
 	"github.com/stretchr/testify/require"
@@ -19,6 +22,27 @@ func GoldenLocks(t *testing.T, newLocks LocksConstructor[string]) {
 
 		testLock(t, ctx, store)
 	})
+	t.Run("TryLock", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newLocks()
+		require.NoError(err)
+
+		testTryLock(t, ctx, store)
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newLocks()
+		require.NoError(err)
+
+		testLockCancel(t, ctx, store)
+	})
+	t.Run("Contention", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newLocks()
+		require.NoError(err)
+
+		testLockContention(t, ctx, store)
+	})
 }
 
 func testLock(t *testing.T, ctx context.Context, store kv.Locks[string]) {
@@ -30,3 +54,192 @@ func testLock(t *testing.T, ctx context.Context, store kv.Locks[string]) {
 	err = store.Unlock(ctx, "key")
 	require.NoError(err)
 }
+
+func testTryLock(t *testing.T, ctx context.Context, store kv.Locks[string]) {
+	require := require.New(t)
+
+	ok, err := store.TryLock(ctx, "key")
+	require.NoError(err)
+	require.True(ok)
+
+	ok, err = store.TryLock(ctx, "key")
+	require.NoError(err)
+	require.False(ok)
+
+	ok, err = store.TryLock(ctx, "other")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(store.Unlock(ctx, "other"))
+
+	require.NoError(store.Unlock(ctx, "key"))
+
+	ok, err = store.TryLock(ctx, "key")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(store.Unlock(ctx, "key"))
+}
+
+func testLockCancel(t *testing.T, ctx context.Context, store kv.Locks[string]) {
+	require := require.New(t)
+
+	// the lock is held by another goroutine, as waiting for a lock held by the same goroutine may be reported as a deadlock
+	locked := make(chan error)
+	go func() {
+		locked <- store.Lock(ctx, "key")
+	}()
+	require.NoError(<-locked)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	err := store.Lock(timeoutCtx, "key")
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- store.Lock(cancelCtx, "key")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		require.ErrorIs(err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		require.Fail("lock must return after cancellation")
+	}
+
+	// cancelled waiters don't hold the lock
+	require.NoError(store.Unlock(ctx, "key"))
+	ok, err := store.TryLock(ctx, "key")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(store.Unlock(ctx, "key"))
+}
+
+func testLockContention(t *testing.T, ctx context.Context, store kv.Locks[string]) {
+	require := require.New(t)
+
+	const workers, iterations = 8, 10
+	var holders, violations atomic.Int32
+	errs := make(chan error, workers*iterations*2)
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				if err := store.Lock(ctx, "key"); err != nil {
+					errs <- err
+					return
+				}
+				if holders.Add(1) > 1 {
+					violations.Add(1)
+				}
+				time.Sleep(time.Millisecond)
+				holders.Add(-1)
+				errs <- store.Unlock(ctx, "key")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	require.Zero(violations.Load())
+}
+
+// StaleLocksConstructor creates two independent lock instances sharing the same storage, as two processes would.
+type StaleLocksConstructor func(ttl time.Duration) (kv.Locks[string], kv.Locks[string], error)
+
+func GoldenStaleLocks(t *testing.T, newLocks StaleLocksConstructor) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	first, second, err := newLocks(200 * time.Millisecond)
+	require.NoError(err)
+
+	require.NoError(first.Lock(ctx, "key"))
+
+	ok, err := second.TryLock(ctx, "key")
+	require.NoError(err)
+	require.False(ok)
+
+	// the first holder crashed, its lock is reclaimed after expiration
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(second.Lock(timeoutCtx, "key"))
+
+	require.ErrorIs(first.Unlock(ctx, "key"), kv.ErrLockNotHeld)
+
+	ok, err = first.TryLock(ctx, "key")
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(second.Unlock(ctx, "key"))
+	require.NoError(first.Lock(ctx, "key"))
+	require.NoError(first.Close(ctx))
+
+	ok, err = second.TryLock(ctx, "key")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(second.Close(ctx))
+}
+
+// LocksWithDataConstructor creates a store and locks sharing the same storage.
+type LocksWithDataConstructor func() (kv.Store[string, string], kv.Locks[string], error)
+
+// GoldenLocksWithData checks that locks of keys holding data neither change the data nor show up in its ranges.
+func GoldenLocksWithData(t *testing.T, newStoreAndLocks LocksWithDataConstructor) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store, locks, err := newStoreAndLocks()
+	require.NoError(err)
+
+	require.NoError(store.Set(ctx, "data/key", "value"))
+	require.NoError(store.Set(ctx, "data/other", "other"))
+
+	ok, err := locks.TryLock(ctx, "data/key")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(locks.Lock(ctx, "data/unset"))
+
+	v, err := store.Get(ctx, "data/key")
+	require.NoError(err)
+	require.Equal("value", v)
+
+	require.NoError(store.Set(ctx, "data/key", "changed"))
+	ok, err = locks.TryLock(ctx, "data/key")
+	require.NoError(err)
+	require.False(ok)
+
+	got := map[string]string{}
+	err = store.RangeWithPrefix(ctx, "data/", func(k, v string) error {
+		got[k] = v
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{"data/key": "changed", "data/other": "other"}, got)
+
+	n, err := kv.Count(ctx, store, "data/")
+	require.NoError(err)
+	require.Equal(2, n)
+
+	ok, err = kv.Has(ctx, store, "data/unset")
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(store.Delete(ctx, "data/key"))
+	ok, err = locks.TryLock(ctx, "data/key")
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(locks.Unlock(ctx, "data/key"))
+	require.NoError(locks.Unlock(ctx, "data/unset"))
+
+	v, err = store.Get(ctx, "data/other")
+	require.NoError(err)
+	require.Equal("other", v)
+}
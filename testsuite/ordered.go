@@ -0,0 +1,44 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func testRangeOrdered(t *testing.T, ctx context.Context, store kv.Store[string, string]) {
+	require := require.New(t)
+
+	ordered, ok := store.(kv.StoreOrdered[string, string])
+	if !ok {
+		t.Skip("store does not implement kv.StoreOrdered")
+	}
+
+	for _, k := range []string{"c", "a", "e", "b", "d"} {
+		err := store.Set(ctx, k, "value-"+k)
+		require.NoError(err)
+	}
+
+	collect := func(order kv.Order[string]) []string {
+		keys := []string{}
+		err := ordered.RangeOrdered(ctx, order, func(k, v string) error {
+			require.Equal("value-"+k, v)
+			keys = append(keys, k)
+			return nil
+		})
+		require.NoError(err)
+		return keys
+	}
+
+	require.Equal([]string{"a", "b", "c", "d", "e"}, collect(kv.Order[string]{}))
+	require.Equal([]string{"e", "d", "c", "b", "a"}, collect(kv.Order[string]{Reverse: true}))
+	require.Equal([]string{"b", "c"}, collect(kv.Order[string]{Min: "b", Max: "d"}))
+	require.Equal([]string{"c", "b"}, collect(kv.Order[string]{Min: "b", Max: "d", Reverse: true}))
+	require.Equal([]string{"c", "d", "e"}, collect(kv.Order[string]{Min: "bb"}))
+	require.Equal([]string{"b", "a"}, collect(kv.Order[string]{Max: "bb", Reverse: true}))
+
+	err := store.Close(ctx)
+	require.NoError(err)
+}
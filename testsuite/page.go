@@ -0,0 +1,143 @@
+package testsuite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func testRangePage(t *testing.T, ctx context.Context, store kv.Store[string, string]) {
+	require := require.New(t)
+
+	expectedKeys := []string{}
+	for i := range 7 {
+		k := fmt.Sprintf("key%d", i)
+		err := store.Set(ctx, k, "value")
+		require.NoError(err)
+		expectedKeys = append(expectedKeys, k)
+	}
+
+	collect := func(reverse bool) []string {
+		keys := []string{}
+		opts := kv.PageOptions{Limit: 3, Reverse: reverse}
+		for {
+			page, err := kv.RangePage(ctx, store, opts)
+			require.NoError(err)
+			require.LessOrEqual(len(page.Items), opts.Limit)
+			for _, item := range page.Items {
+				keys = append(keys, item.Key)
+			}
+			if page.Next == "" {
+				return keys
+			}
+			opts.Cursor = page.Next
+		}
+	}
+
+	require.Equal(expectedKeys, collect(false))
+	slices.Reverse(expectedKeys)
+	require.Equal(expectedKeys, collect(true))
+
+	_, err := kv.RangePage(ctx, store, kv.PageOptions{Limit: 0})
+	require.ErrorIs(err, kv.ErrInvalidLimit)
+
+	_, err = kv.RangePage(ctx, store, kv.PageOptions{Limit: 1, Cursor: "!"})
+	require.ErrorIs(err, kv.ErrInvalidCursor)
+
+	err = store.Close(ctx)
+	require.NoError(err)
+}
+
+// GoldenPageBytes checks that pages of a []byte store stay intact after the store is modified and closed,
+// as backends may return slices which are valid only during the read.
+func GoldenPageBytes(t *testing.T, newKV StoreConstructor[[]byte, []byte]) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store, err := newKV()
+	require.NoError(err)
+
+	// values are large enough not to be inlined by the backend
+	value := func(i, gen int) []byte {
+		return bytes.Repeat([]byte{byte('a' + gen)}, 512+i)
+	}
+
+	for i := range 10 {
+		err := store.Set(ctx, []byte(fmt.Sprintf("key%d", i)), value(i, 0))
+		require.NoError(err)
+	}
+
+	var items []kv.Item[[]byte, []byte]
+	opts := kv.PageOptions{Limit: 3}
+	for {
+		page, err := kv.RangePage(ctx, store, opts)
+		require.NoError(err)
+		items = append(items, page.Items...)
+		if page.Next == "" {
+			break
+		}
+		opts.Cursor = page.Next
+	}
+	require.Len(items, 10)
+
+	// rewrite the values and grow the store, so the pages read before are reused or remapped
+	for i := range 10 {
+		err := store.Set(ctx, []byte(fmt.Sprintf("key%d", i)), value(i, 1))
+		require.NoError(err)
+	}
+	for i := range 1000 {
+		err := store.Set(ctx, []byte(fmt.Sprintf("other%d", i)), value(i, 2))
+		require.NoError(err)
+	}
+
+	// the items must outlive the store too
+	err = store.Close(ctx)
+	require.NoError(err)
+
+	for i, item := range items {
+		require.Equal([]byte(fmt.Sprintf("key%d", i)), item.Key)
+		require.Equal(value(i, 0), item.Value)
+	}
+}
+
+// GoldenPageEmptyKey checks that pagination continues after a page ending with the empty key,
+// for stores accepting the empty key.
+func GoldenPageEmptyKey(t *testing.T, newKV StoreConstructor[string, string]) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store, err := newKV()
+	require.NoError(err)
+
+	expectedKeys := []string{"", "a", "b"}
+	for _, k := range expectedKeys {
+		require.NoError(store.Set(ctx, k, "value"))
+	}
+
+	collect := func(reverse bool) []string {
+		keys := []string{}
+		opts := kv.PageOptions{Limit: 1, Reverse: reverse}
+		for {
+			page, err := kv.RangePage(ctx, store, opts)
+			require.NoError(err)
+			for _, item := range page.Items {
+				keys = append(keys, item.Key)
+			}
+			if page.Next == "" {
+				return keys
+			}
+			opts.Cursor = page.Next
+		}
+	}
+
+	require.Equal(expectedKeys, collect(false))
+	slices.Reverse(expectedKeys)
+	require.Equal(expectedKeys, collect(true))
+
+	require.NoError(store.Close(ctx))
+}
@@ -0,0 +1,175 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv/queue"
+	"github.com/stretchr/testify/require"
+)
+
+type QueueStoreConstructor func() (queue.Store, error)
+
+func GoldenQueue(t *testing.T, newStore QueueStoreConstructor) {
+	ctx := context.Background()
+	newQueue := func(t *testing.T, opts queue.Options[string]) *queue.Queue[string] {
+		store, err := newStore()
+		require.NoError(t, err)
+		return queue.New(store, "test", opts)
+	}
+	opts := queue.DefaultOptions[string]()
+	opts.PollInterval = 10 * time.Millisecond
+
+	t.Run("FIFO", func(t *testing.T) {
+		testQueueFIFO(t, ctx, newQueue(t, opts))
+	})
+	t.Run("Visibility Timeout", func(t *testing.T) {
+		opts := opts
+		opts.VisibilityTimeout = 200 * time.Millisecond
+		testQueueVisibilityTimeout(t, ctx, newQueue(t, opts))
+	})
+	t.Run("Dead Letter", func(t *testing.T) {
+		opts := opts
+		opts.MaxAttempts = 2
+		testQueueDeadLetter(t, ctx, newQueue(t, opts))
+	})
+	t.Run("Delayed", func(t *testing.T) {
+		testQueueDelayed(t, ctx, newQueue(t, opts))
+	})
+	t.Run("Blocking", func(t *testing.T) {
+		opts := opts
+		opts.PollInterval = time.Minute
+		testQueueBlocking(t, ctx, newQueue(t, opts))
+	})
+}
+
+func testQueueFIFO(t *testing.T, ctx context.Context, q *queue.Queue[string]) {
+	require := require.New(t)
+
+	for _, payload := range []string{"first", "second", "third"} {
+		_, err := q.Enqueue(ctx, payload)
+		require.NoError(err)
+	}
+
+	for _, payload := range []string{"first", "second", "third"} {
+		msg, err := q.TryDequeue(ctx)
+		require.NoError(err)
+		require.Equal(payload, msg.Payload)
+		require.Equal(1, msg.Attempts)
+
+		err = q.Ack(ctx, msg)
+		require.NoError(err)
+	}
+
+	_, err := q.TryDequeue(ctx)
+	require.ErrorIs(err, queue.ErrEmpty)
+}
+
+func testQueueVisibilityTimeout(t *testing.T, ctx context.Context, q *queue.Queue[string]) {
+	require := require.New(t)
+
+	id, err := q.Enqueue(ctx, "payload")
+	require.NoError(err)
+
+	first, err := q.TryDequeue(ctx)
+	require.NoError(err)
+	require.Equal(id, first.ID)
+
+	_, err = q.TryDequeue(ctx)
+	require.ErrorIs(err, queue.ErrEmpty)
+
+	time.Sleep(300 * time.Millisecond)
+
+	second, err := q.TryDequeue(ctx)
+	require.NoError(err)
+	require.Equal(id, second.ID)
+	require.Equal(2, second.Attempts)
+
+	err = q.Ack(ctx, first)
+	require.ErrorIs(err, queue.ErrLeaseLost)
+
+	err = q.Ack(ctx, second)
+	require.NoError(err)
+
+	_, err = q.TryDequeue(ctx)
+	require.ErrorIs(err, queue.ErrEmpty)
+}
+
+func testQueueDeadLetter(t *testing.T, ctx context.Context, q *queue.Queue[string]) {
+	require := require.New(t)
+
+	id, err := q.Enqueue(ctx, "payload")
+	require.NoError(err)
+
+	msg, err := q.TryDequeue(ctx)
+	require.NoError(err)
+	err = q.Nack(ctx, msg, 0)
+	require.NoError(err)
+
+	msg, err = q.TryDequeue(ctx)
+	require.NoError(err)
+	require.Equal(2, msg.Attempts)
+	err = q.Nack(ctx, msg, 0)
+	require.NoError(err)
+
+	_, err = q.TryDequeue(ctx)
+	require.ErrorIs(err, queue.ErrEmpty)
+
+	dead := []string{}
+	err = q.RangeDeadLetters(ctx, func(msg queue.Message[string]) error {
+		require.Equal("payload", msg.Payload)
+		dead = append(dead, msg.ID)
+		return nil
+	})
+	require.NoError(err)
+	require.Equal([]string{id}, dead)
+
+	err = q.DeleteDeadLetter(ctx, id)
+	require.NoError(err)
+
+	err = q.RangeDeadLetters(ctx, func(msg queue.Message[string]) error {
+		t.Fatalf("unexpected dead letter %s", msg.ID)
+		return nil
+	})
+	require.NoError(err)
+}
+
+func testQueueDelayed(t *testing.T, ctx context.Context, q *queue.Queue[string]) {
+	require := require.New(t)
+
+	_, err := q.EnqueueDelayed(ctx, "delayed", 200*time.Millisecond)
+	require.NoError(err)
+
+	_, err = q.TryDequeue(ctx)
+	require.ErrorIs(err, queue.ErrEmpty)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	msg, err := q.Dequeue(ctx)
+	require.NoError(err)
+	require.Equal("delayed", msg.Payload)
+	require.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+}
+
+func testQueueBlocking(t *testing.T, ctx context.Context, q *queue.Queue[string]) {
+	require := require.New(t)
+
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err := q.Dequeue(cancelCtx)
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = q.Enqueue(ctx, "payload")
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	msg, err := q.Dequeue(waitCtx)
+	require.NoError(err)
+	require.Equal("payload", msg.Payload)
+}
@@ -0,0 +1,208 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+type TokenBucketConstructor func(opts ratelimit.TokenBucketOptions) (ratelimit.Limiter[string], error)
+
+type SlidingWindowConstructor func(opts ratelimit.SlidingWindowOptions) (ratelimit.Limiter[string], error)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func GoldenTokenBucket(t *testing.T, newLimiter TokenBucketConstructor) {
+	ctx := context.Background()
+	t.Run("Refill", func(t *testing.T) {
+		require := require.New(t)
+		clock := newFakeClock()
+		l, err := newLimiter(ratelimit.TokenBucketOptions{Rate: 2, Burst: 5, Clock: clock.Now})
+		require.NoError(err)
+
+		_, err = l.Allow(ctx, "a", 0)
+		require.ErrorIs(err, ratelimit.ErrInvalidN)
+
+		requireAllowed(t, ctx, l, "a", 1, 5)
+		requireAllowed(t, ctx, l, "b", 5, 1)
+
+		// requests larger than the available tokens don't consume them
+		clock.Advance(time.Second)
+		requireAllowed(t, ctx, l, "a", 3, 0)
+		requireAllowed(t, ctx, l, "a", 2, 1)
+
+		// refill is capped by the burst
+		clock.Advance(time.Hour)
+		requireAllowed(t, ctx, l, "a", 6, 0)
+		requireAllowed(t, ctx, l, "a", 5, 1)
+
+		clock.Advance(250 * time.Millisecond)
+		requireAllowed(t, ctx, l, "a", 1, 0)
+		clock.Advance(250 * time.Millisecond)
+		requireAllowed(t, ctx, l, "a", 1, 1)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		require := require.New(t)
+		clock := newFakeClock()
+		l, err := newLimiter(ratelimit.TokenBucketOptions{Rate: 1, Burst: 50, Clock: clock.Now})
+		require.NoError(err)
+
+		require.Equal(50, allowConcurrent(t, ctx, l, "a", 100))
+	})
+}
+
+func GoldenSlidingWindow(t *testing.T, newLimiter SlidingWindowConstructor) {
+	ctx := context.Background()
+	t.Run("Window", func(t *testing.T) {
+		require := require.New(t)
+		clock := newFakeClock()
+		l, err := newLimiter(ratelimit.SlidingWindowOptions{Limit: 10, Window: time.Minute, Clock: clock.Now})
+		require.NoError(err)
+
+		_, err = l.Allow(ctx, "a", -1)
+		require.ErrorIs(err, ratelimit.ErrInvalidN)
+
+		requireAllowed(t, ctx, l, "a", 4, 2)
+		requireAllowed(t, ctx, l, "a", 4, 0)
+		requireAllowed(t, ctx, l, "a", 2, 1)
+		requireAllowed(t, ctx, l, "b", 10, 1)
+
+		// half of the previous window is still counted
+		clock.Advance(90 * time.Second)
+		requireAllowed(t, ctx, l, "a", 6, 0)
+		requireAllowed(t, ctx, l, "a", 5, 1)
+
+		// the whole previous window is counted at its end
+		clock.Advance(30 * time.Second)
+		requireAllowed(t, ctx, l, "a", 6, 0)
+		requireAllowed(t, ctx, l, "a", 5, 1)
+
+		// the previous window has passed completely
+		clock.Advance(2 * time.Minute)
+		requireAllowed(t, ctx, l, "a", 10, 1)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		require := require.New(t)
+		clock := newFakeClock()
+		l, err := newLimiter(ratelimit.SlidingWindowOptions{Limit: 50, Window: time.Minute, Clock: clock.Now})
+		require.NoError(err)
+
+		require.Equal(50, allowConcurrent(t, ctx, l, "a", 100))
+	})
+}
+
+// GoldenRateLimitExpiry checks that limiter states expire in stores implementing kv.StoreTTL.
+func GoldenRateLimitExpiry(t *testing.T, newBuckets StoreConstructor[string, ratelimit.TokenBucketState], newWindows StoreConstructor[string, ratelimit.SlidingWindowState]) {
+	ctx := context.Background()
+	t.Run("TokenBucket", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newBuckets()
+		require.NoError(err)
+		defer store.Close(ctx)
+
+		// the bucket is full again in 100ms
+		l := ratelimit.NewTokenBucket(store, ratelimit.TokenBucketOptions{Rate: 10, Burst: 1})
+		requireAllowed(t, ctx, l, "a", 1, 1)
+		requireExpired(t, ctx, store, "a")
+	})
+	t.Run("SlidingWindow", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newWindows()
+		require.NoError(err)
+		defer store.Close(ctx)
+
+		l := ratelimit.NewSlidingWindow(store, ratelimit.SlidingWindowOptions{Limit: 1, Window: 50 * time.Millisecond})
+		ok, err := l.Allow(ctx, "a", 1)
+		require.NoError(err)
+		require.True(ok)
+		requireExpired(t, ctx, store, "a")
+	})
+}
+
+// requireExpired requires that the key is stored now and expires soon.
+func requireExpired[V any](t *testing.T, ctx context.Context, store kv.Store[string, V], key string) {
+	t.Helper()
+	require := require.New(t)
+
+	ok, err := kv.Has(ctx, store, key)
+	require.NoError(err)
+	require.True(ok)
+
+	require.Eventually(func() bool {
+		ok, err := kv.Has(ctx, store, key)
+		require.NoError(err)
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// requireAllowed requires that the given number of sequential requests of n events are allowed and the next one is denied.
+func requireAllowed(t *testing.T, ctx context.Context, l ratelimit.Limiter[string], key string, n int, times int) {
+	t.Helper()
+	require := require.New(t)
+
+	for range times {
+		ok, err := l.Allow(ctx, key, n)
+		require.NoError(err)
+		require.True(ok)
+	}
+	ok, err := l.Allow(ctx, key, n)
+	require.NoError(err)
+	require.False(ok)
+}
+
+// allowConcurrent makes the given number of concurrent single event requests and returns the number of allowed ones.
+func allowConcurrent(t *testing.T, ctx context.Context, l ratelimit.Limiter[string], key string, requests int) int {
+	require := require.New(t)
+
+	results := make(chan bool, requests)
+	errs := make(chan error, requests)
+	wg := sync.WaitGroup{}
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := l.Allow(ctx, key, 1)
+			results <- ok
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	allowed := 0
+	for ok := range results {
+		if ok {
+			allowed++
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,153 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+type RWLocksConstructor[K any] func() (kv.RWLocks[K], error)
+
+func GoldenRWLocks(t *testing.T, newLocks RWLocksConstructor[string]) {
+	GoldenLocks(t, func() (kv.Locks[string], error) {
+		return newLocks()
+	})
+
+	ctx := context.Background()
+	t.Run("Shared", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testRWLockShared(t, ctx, locks)
+	})
+	t.Run("RW Contention", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testRWLockContention(t, ctx, locks)
+	})
+	t.Run("Unheld RUnlock", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+
+		testRWLockUnheldRUnlock(t, ctx, locks)
+	})
+}
+
+func testRWLockShared(t *testing.T, ctx context.Context, locks kv.RWLocks[string]) {
+	require := require.New(t)
+
+	require.NoError(locks.RLock(ctx, "key"))
+	require.NoError(locks.RLock(ctx, "key"))
+
+	ok, err := locks.TryLock(ctx, "key")
+	require.NoError(err)
+	require.False(ok)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	err = locks.Lock(timeoutCtx, "key")
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	require.NoError(locks.RUnlock(ctx, "key"))
+	require.NoError(locks.RUnlock(ctx, "key"))
+
+	require.NoError(locks.Lock(ctx, "key"))
+
+	timeoutCtx, cancel = context.WithTimeout(ctx, 100*time.Millisecond)
+	err = locks.RLock(timeoutCtx, "key")
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	require.NoError(locks.Unlock(ctx, "key"))
+
+	require.NoError(locks.RLock(ctx, "key"))
+	require.NoError(locks.RUnlock(ctx, "key"))
+}
+
+func testRWLockContention(t *testing.T, ctx context.Context, locks kv.RWLocks[string]) {
+	require := require.New(t)
+
+	const readers, writers, iterations = 6, 2, 10
+	var active, writing, violations atomic.Int32
+	var sharedReaders atomic.Bool
+	errs := make(chan error, (readers+writers)*iterations*2)
+	wg := sync.WaitGroup{}
+	for i := range readers + writers {
+		write := i < writers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				var err error
+				if write {
+					err = locks.Lock(ctx, "key")
+				} else {
+					err = locks.RLock(ctx, "key")
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if write {
+					if writing.Add(1) > 1 || active.Load() > 0 {
+						violations.Add(1)
+					}
+					time.Sleep(time.Millisecond)
+					writing.Add(-1)
+					errs <- locks.Unlock(ctx, "key")
+				} else {
+					n := active.Add(1)
+					if writing.Load() > 0 {
+						violations.Add(1)
+					}
+					if n > 1 {
+						sharedReaders.Store(true)
+					}
+					time.Sleep(5 * time.Millisecond)
+					active.Add(-1)
+					errs <- locks.RUnlock(ctx, "key")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	require.Zero(violations.Load())
+	require.True(sharedReaders.Load(), "readers must share the lock")
+}
+
+func testRWLockUnheldRUnlock(t *testing.T, ctx context.Context, locks kv.RWLocks[string]) {
+	require := require.New(t)
+
+	require.Error(locks.RUnlock(ctx, "key"))
+
+	require.NoError(locks.RLock(ctx, "key"))
+	require.NoError(locks.RUnlock(ctx, "key"))
+	require.Error(locks.RUnlock(ctx, "key"))
+
+	// readers must not be left below zero, so a reader blocks a writer again
+	require.NoError(locks.RLock(ctx, "key"))
+	ok, err := locks.TryLock(ctx, "key")
+	require.NoError(err)
+	require.False(ok)
+	require.NoError(locks.RUnlock(ctx, "key"))
+
+	ok, err = locks.TryLock(ctx, "key")
+	require.NoError(err)
+	require.True(ok)
+	require.NoError(locks.Unlock(ctx, "key"))
+}
@@ -0,0 +1,196 @@
+package testsuite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv/semaphore"
+	"github.com/stretchr/testify/require"
+)
+
+func GoldenSemaphore(t *testing.T, newLocks LocksConstructor[string], newStore StoreConstructor[string, semaphore.State]) {
+	ctx := context.Background()
+	newSemaphore := func(t *testing.T, size int64, ttl time.Duration) *semaphore.Semaphore {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+		store, err := newStore()
+		require.NoError(err)
+
+		s, err := semaphore.New(store, locks, semaphore.Options{
+			Size:         size,
+			TTL:          ttl,
+			PollInterval: 10 * time.Millisecond,
+		})
+		require.NoError(err)
+		return s
+	}
+
+	t.Run("Options", func(t *testing.T) {
+		require := require.New(t)
+		locks, err := newLocks()
+		require.NoError(err)
+		store, err := newStore()
+		require.NoError(err)
+
+		// waiters would expire between their checks
+		_, err = semaphore.New(store, locks, semaphore.Options{Size: 1, TTL: time.Second, PollInterval: time.Second})
+		require.ErrorIs(err, semaphore.ErrInvalidPoll)
+		_, err = semaphore.New(store, locks, semaphore.Options{Size: 1, TTL: time.Second})
+		require.ErrorIs(err, semaphore.ErrInvalidPoll)
+	})
+	t.Run("Weights", func(t *testing.T) {
+		testSemaphoreWeights(t, ctx, newSemaphore(t, 3, time.Minute))
+	})
+	t.Run("Fairness", func(t *testing.T) {
+		testSemaphoreFairness(t, ctx, newSemaphore(t, 3, time.Minute))
+	})
+	t.Run("Expiry", func(t *testing.T) {
+		testSemaphoreExpiry(t, ctx, newSemaphore(t, 2, 200*time.Millisecond))
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		testSemaphoreConcurrent(t, ctx, newSemaphore(t, 3, time.Minute))
+	})
+}
+
+func testSemaphoreWeights(t *testing.T, ctx context.Context, s *semaphore.Semaphore) {
+	require := require.New(t)
+
+	_, err := s.Acquire(ctx, "key", 0)
+	require.ErrorIs(err, semaphore.ErrInvalidWeight)
+	_, err = s.Acquire(ctx, "key", 4)
+	require.ErrorIs(err, semaphore.ErrInvalidWeight)
+
+	p1, err := s.Acquire(ctx, "key", 2)
+	require.NoError(err)
+	p2, err := s.Acquire(ctx, "key", 1)
+	require.NoError(err)
+
+	// keys are independent
+	other, err := s.Acquire(ctx, "other", 3)
+	require.NoError(err)
+	require.NoError(s.Release(ctx, other))
+
+	available, err := s.Available(ctx, "key")
+	require.NoError(err)
+	require.Zero(available)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = s.Acquire(timeoutCtx, "key", 1)
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	require.NoError(s.Release(ctx, p1))
+	require.ErrorIs(s.Release(ctx, p1), semaphore.ErrNotHeld)
+
+	p3, err := s.Acquire(ctx, "key", 2)
+	require.NoError(err)
+
+	require.NoError(s.Release(ctx, p2))
+	require.NoError(s.Release(ctx, p3))
+
+	available, err = s.Available(ctx, "key")
+	require.NoError(err)
+	require.Equal(int64(3), available)
+}
+
+func testSemaphoreFairness(t *testing.T, ctx context.Context, s *semaphore.Semaphore) {
+	require := require.New(t)
+
+	held, err := s.Acquire(ctx, "key", 2)
+	require.NoError(err)
+
+	heavy := make(chan semaphore.Permit)
+	go func() {
+		p, err := s.Acquire(ctx, "key", 2)
+		if err == nil {
+			heavy <- p
+		}
+		close(heavy)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// a permit is free, but the heavy waiter came first
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = s.Acquire(timeoutCtx, "key", 1)
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	require.NoError(s.Release(ctx, held))
+	p, ok := <-heavy
+	require.True(ok)
+
+	light, err := s.Acquire(ctx, "key", 1)
+	require.NoError(err)
+
+	require.NoError(s.Release(ctx, p))
+	require.NoError(s.Release(ctx, light))
+}
+
+func testSemaphoreExpiry(t *testing.T, ctx context.Context, s *semaphore.Semaphore) {
+	require := require.New(t)
+
+	crashed, err := s.Acquire(ctx, "key", 2)
+	require.NoError(err)
+
+	renewed, err := s.Acquire(ctx, "other", 2)
+	require.NoError(err)
+
+	// the holder of the key crashed, its permit is reclaimed after the ttl
+	for range 3 {
+		time.Sleep(100 * time.Millisecond)
+		require.NoError(s.Renew(ctx, renewed))
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	p, err := s.Acquire(timeoutCtx, "key", 2)
+	require.NoError(err)
+
+	require.ErrorIs(s.Release(ctx, crashed), semaphore.ErrNotHeld)
+	require.ErrorIs(s.Renew(ctx, crashed), semaphore.ErrNotHeld)
+	require.NoError(s.Release(ctx, p))
+
+	available, err := s.Available(ctx, "other")
+	require.NoError(err)
+	require.Zero(available)
+	require.NoError(s.Release(ctx, renewed))
+}
+
+func testSemaphoreConcurrent(t *testing.T, ctx context.Context, s *semaphore.Semaphore) {
+	require := require.New(t)
+
+	const workers, iterations = 8, 5
+	var active, violations atomic.Int32
+	errs := make(chan error, workers*iterations)
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				p, err := s.Acquire(ctx, "key", 1)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if active.Add(1) > 3 {
+					violations.Add(1)
+				}
+				time.Sleep(time.Millisecond)
+				active.Add(-1)
+				errs <- s.Release(ctx, p)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+	require.Zero(violations.Load())
+}
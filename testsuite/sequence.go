@@ -0,0 +1,106 @@
+package testsuite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+// SequenceConstructor creates a sequence for the given key,
+// sequences created with the same key must share the same persisted state.
+type SequenceConstructor func(key string) (kv.Sequence, error)
+
+func GoldenSequence(t *testing.T, newSequence SequenceConstructor) {
+	ctx := context.Background()
+	t.Run("Monotonic", func(t *testing.T) {
+		require := require.New(t)
+		seq, err := newSequence("monotonic")
+		require.NoError(err)
+
+		testSequenceMonotonic(t, ctx, seq)
+	})
+	t.Run("Restart", func(t *testing.T) {
+		testSequenceRestart(t, ctx, newSequence)
+	})
+	t.Run("Keys", func(t *testing.T) {
+		require := require.New(t)
+		seq, err := newSequence("keys")
+		require.NoError(err)
+
+		testSequenceKeys(t, ctx, seq)
+	})
+}
+
+func testSequenceMonotonic(t *testing.T, ctx context.Context, seq kv.Sequence) {
+	require := require.New(t)
+
+	prev := uint64(0)
+	for range 50 {
+		id, err := seq.Next(ctx)
+		require.NoError(err)
+		require.Greater(id, prev)
+		prev = id
+	}
+
+	err := seq.Release(ctx)
+	require.NoError(err)
+}
+
+func testSequenceRestart(t *testing.T, ctx context.Context, newSequence SequenceConstructor) {
+	require := require.New(t)
+
+	seq1, err := newSequence("restart")
+	require.NoError(err)
+	seq2, err := newSequence("restart")
+	require.NoError(err)
+
+	ids := map[uint64]bool{}
+	last := uint64(0)
+	for range 25 {
+		for _, seq := range []kv.Sequence{seq1, seq2} {
+			id, err := seq.Next(ctx)
+			require.NoError(err)
+			require.False(ids[id], "duplicate id %d", id)
+			ids[id] = true
+			last = max(last, id)
+		}
+	}
+
+	err = seq1.Release(ctx)
+	require.NoError(err)
+	err = seq2.Release(ctx)
+	require.NoError(err)
+
+	// simulates a restart of the process
+	seq3, err := newSequence("restart")
+	require.NoError(err)
+
+	id, err := seq3.Next(ctx)
+	require.NoError(err)
+	require.Greater(id, last)
+}
+
+func testSequenceKeys(t *testing.T, ctx context.Context, seq kv.Sequence) {
+	require := require.New(t)
+
+	prevKey := ""
+	for range 300 {
+		id, err := seq.Next(ctx)
+		require.NoError(err)
+
+		key := kv.SequenceKey("prefix/", id)
+		require.True(strings.HasPrefix(key, "prefix/"))
+		require.Less(prevKey, key)
+		prevKey = key
+
+		parsed, err := kv.SequenceID(key)
+		require.NoError(err)
+		require.Equal(id, parsed)
+	}
+
+	_, err := kv.SequenceID("short")
+	require.ErrorIs(err, kv.ErrInvalidSequenceKey)
+}
@@ -0,0 +1,169 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/royalcat/kv/timeseries"
+	"github.com/stretchr/testify/require"
+)
+
+type TimeSeriesStoreConstructor func() (timeseries.Store, error)
+
+func GoldenTimeSeries(t *testing.T, newStore TimeSeriesStoreConstructor) {
+	ctx := context.Background()
+	t.Run("Range", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testTimeSeriesRange(t, ctx, timeseries.New(store, "metrics"))
+	})
+	t.Run("Aggregate", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testTimeSeriesAggregate(t, ctx, timeseries.New(store, "metrics"))
+	})
+	t.Run("Retention", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testTimeSeriesRetention(t, ctx, timeseries.New(store, "metrics"))
+	})
+}
+
+var timeSeriesEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testTimeSeriesRange(t *testing.T, ctx context.Context, ts *timeseries.TimeSeries) {
+	require := require.New(t)
+
+	// written out of order, before and after the epoch
+	for _, i := range []int{3, -2, 0, 1, 2, -1} {
+		require.NoError(ts.Add(ctx, "cpu", timeseries.Point{
+			Time:  timeSeriesEpoch.Add(time.Duration(i) * time.Second),
+			Value: float64(i),
+		}))
+	}
+	require.NoError(ts.Add(ctx, "cpu2", timeseries.Point{Time: timeSeriesEpoch, Value: 100}))
+	require.ErrorIs(ts.Add(ctx, "", timeseries.Point{}), timeseries.ErrInvalidSeries)
+	require.ErrorIs(ts.Add(ctx, "a\x00b", timeseries.Point{}), timeseries.ErrInvalidSeries)
+	// no point is written when any time is invalid
+	require.ErrorIs(ts.Add(ctx, "cpu",
+		timeseries.Point{Time: timeSeriesEpoch.Add(10 * time.Second), Value: 10},
+		timeseries.Point{Value: 11},
+	), timeseries.ErrInvalidTime)
+	require.ErrorIs(ts.Add(ctx, "cpu", timeseries.Point{Time: time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)}), timeseries.ErrInvalidTime)
+
+	collect := func(from, to time.Time) []float64 {
+		values := []float64{}
+		require.NoError(ts.Range(ctx, "cpu", from, to, func(p timeseries.Point) error {
+			values = append(values, p.Value)
+			return nil
+		}))
+		return values
+	}
+
+	require.Equal([]float64{-2, -1, 0, 1, 2, 3}, collect(time.Time{}, time.Time{}))
+	require.Equal([]float64{-1, 0, 1}, collect(timeSeriesEpoch.Add(-time.Second), timeSeriesEpoch.Add(2*time.Second)))
+	require.Equal([]float64{2, 3}, collect(timeSeriesEpoch.Add(2*time.Second), time.Time{}))
+	require.Equal([]float64{-2, -1}, collect(time.Time{}, timeSeriesEpoch))
+
+	require.NoError(ts.Range(ctx, "cpu", timeSeriesEpoch, timeSeriesEpoch.Add(time.Nanosecond), func(p timeseries.Point) error {
+		require.True(timeSeriesEpoch.Equal(p.Time))
+		return nil
+	}))
+
+	// overwrite
+	require.NoError(ts.Add(ctx, "cpu", timeseries.Point{Time: timeSeriesEpoch, Value: 10}))
+	require.Equal([]float64{10}, collect(timeSeriesEpoch, timeSeriesEpoch.Add(time.Second)))
+}
+
+func testTimeSeriesAggregate(t *testing.T, ctx context.Context, ts *timeseries.TimeSeries) {
+	require := require.New(t)
+
+	// 10 points per minute for 3 minutes, skipping the second minute
+	for m := range 3 {
+		if m == 1 {
+			continue
+		}
+		for i := range 10 {
+			require.NoError(ts.Add(ctx, "cpu", timeseries.Point{
+				Time:  timeSeriesEpoch.Add(time.Duration(m)*time.Minute + time.Duration(i)*6*time.Second),
+				Value: float64(m*10 + i),
+			}))
+		}
+	}
+
+	_, err := ts.Aggregate(ctx, "cpu", time.Time{}, time.Time{}, 0)
+	require.ErrorIs(err, timeseries.ErrInvalidBucket)
+
+	buckets, err := ts.Aggregate(ctx, "cpu", time.Time{}, time.Time{}, time.Minute)
+	require.NoError(err)
+	require.Len(buckets, 2)
+
+	require.True(timeSeriesEpoch.Equal(buckets[0].Start))
+	require.Equal(10, buckets[0].Count)
+	require.Equal(45.0, buckets[0].Sum)
+	require.Equal(0.0, buckets[0].Min)
+	require.Equal(9.0, buckets[0].Max)
+	require.Equal(4.5, buckets[0].Avg())
+
+	require.True(timeSeriesEpoch.Add(2 * time.Minute).Equal(buckets[1].Start))
+	require.Equal(20.0, buckets[1].Value(timeseries.Min))
+	require.Equal(29.0, buckets[1].Value(timeseries.Max))
+	require.Equal(10.0, buckets[1].Value(timeseries.Count))
+	require.Equal(245.0, buckets[1].Value(timeseries.Sum))
+	require.Equal(24.5, buckets[1].Value(timeseries.Avg))
+
+	require.NoError(ts.Downsample(ctx, "cpu", "cpu:1m", time.Time{}, time.Time{}, time.Minute, timeseries.Max))
+
+	points := []timeseries.Point{}
+	require.NoError(ts.Range(ctx, "cpu:1m", time.Time{}, time.Time{}, func(p timeseries.Point) error {
+		points = append(points, p)
+		return nil
+	}))
+	require.Len(points, 2)
+	require.True(timeSeriesEpoch.Equal(points[0].Time))
+	require.Equal(9.0, points[0].Value)
+	require.True(timeSeriesEpoch.Add(2 * time.Minute).Equal(points[1].Time))
+	require.Equal(29.0, points[1].Value)
+}
+
+func testTimeSeriesRetention(t *testing.T, ctx context.Context, ts *timeseries.TimeSeries) {
+	require := require.New(t)
+
+	const total = 2500
+	points := make([]timeseries.Point, 0, total)
+	for i := range total {
+		points = append(points, timeseries.Point{Time: timeSeriesEpoch.Add(time.Duration(i) * time.Second), Value: float64(i)})
+	}
+	require.NoError(ts.Add(ctx, "cpu", points...))
+	require.NoError(ts.Add(ctx, "mem", points[0]))
+
+	// a zero time is not an unbounded window
+	_, err := ts.DeleteBefore(ctx, "cpu", time.Time{})
+	require.ErrorIs(err, timeseries.ErrInvalidTime)
+
+	deleted, err := ts.DeleteBefore(ctx, "cpu", timeSeriesEpoch.Add(2100*time.Second))
+	require.NoError(err)
+	require.Equal(2100, deleted)
+
+	count := 0
+	require.NoError(ts.Range(ctx, "cpu", time.Time{}, time.Time{}, func(p timeseries.Point) error {
+		require.GreaterOrEqual(p.Value, 2100.0)
+		count++
+		return nil
+	}))
+	require.Equal(total-2100, count)
+
+	count = 0
+	require.NoError(ts.Range(ctx, "mem", time.Time{}, time.Time{}, func(p timeseries.Point) error {
+		count++
+		return nil
+	}))
+	require.Equal(1, count)
+}
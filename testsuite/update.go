@@ -0,0 +1,59 @@
+package testsuite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldenUpdate checks that kv.Update applies writes of a successful function and discards writes of a failed one.
+func GoldenUpdate(t *testing.T, newKV StoreConstructor[string, string]) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store, err := newKV()
+	require.NoError(err)
+
+	require.NoError(store.Set(ctx, "kept", "value"))
+
+	errFailed := errors.New("failed")
+	err = kv.Update(ctx, store, func(tx kv.Store[string, string]) error {
+		v, err := tx.Get(ctx, "kept")
+		if err != nil {
+			return err
+		}
+		require.NoError(tx.Set(ctx, "added", v))
+		require.NoError(tx.Delete(ctx, "kept"))
+		return errFailed
+	})
+	require.ErrorIs(err, errFailed)
+
+	ok, err := kv.Has(ctx, store, "added")
+	require.NoError(err)
+	require.False(ok)
+	v, err := store.Get(ctx, "kept")
+	require.NoError(err)
+	require.Equal("value", v)
+
+	err = kv.Update(ctx, store, func(tx kv.Store[string, string]) error {
+		v, err := tx.Get(ctx, "kept")
+		if err != nil {
+			return err
+		}
+		require.NoError(tx.Set(ctx, "added", v))
+		return tx.Delete(ctx, "kept")
+	})
+	require.NoError(err)
+
+	v, err = store.Get(ctx, "added")
+	require.NoError(err)
+	require.Equal("value", v)
+	ok, err = kv.Has(ctx, store, "kept")
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(store.Close(ctx))
+}
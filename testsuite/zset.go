@@ -0,0 +1,175 @@
+package testsuite
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/royalcat/kv"
+	"github.com/royalcat/kv/zset"
+	"github.com/stretchr/testify/require"
+)
+
+type SortedSetStoreConstructor func() (zset.Store, error)
+
+func GoldenSortedSet(t *testing.T, newStore SortedSetStoreConstructor) {
+	ctx := context.Background()
+	t.Run("Scores", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testSortedSetScores(t, ctx, zset.New(store, "test"))
+	})
+	t.Run("Range", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testSortedSetRange(t, ctx, zset.New(store, "test"))
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		require := require.New(t)
+		store, err := newStore()
+		require.NoError(err)
+
+		testSortedSetConcurrent(t, ctx, zset.New(store, "test"))
+	})
+}
+
+func testSortedSetScores(t *testing.T, ctx context.Context, z *zset.SortedSet) {
+	require := require.New(t)
+
+	added, err := z.ZAdd(ctx, "a", 1)
+	require.NoError(err)
+	require.True(added)
+
+	added, err = z.ZAdd(ctx, "a", 5)
+	require.NoError(err)
+	require.False(added)
+
+	score, err := z.ZScore(ctx, "a")
+	require.NoError(err)
+	require.Equal(5.0, score)
+
+	score, err = z.ZIncrBy(ctx, "a", -2.5)
+	require.NoError(err)
+	require.Equal(2.5, score)
+
+	score, err = z.ZIncrBy(ctx, "b", 3)
+	require.NoError(err)
+	require.Equal(3.0, score)
+
+	_, err = z.ZScore(ctx, "missing")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+
+	_, err = z.ZAdd(ctx, "nan", math.NaN())
+	require.ErrorIs(err, zset.ErrInvalidScore)
+
+	n, err := z.ZCard(ctx)
+	require.NoError(err)
+	require.Equal(2, n)
+
+	removed, err := z.ZRem(ctx, "a")
+	require.NoError(err)
+	require.True(removed)
+
+	removed, err = z.ZRem(ctx, "a")
+	require.NoError(err)
+	require.False(removed)
+
+	n, err = z.ZCount(ctx, math.Inf(-1), math.Inf(1))
+	require.NoError(err)
+	require.Equal(1, n)
+}
+
+func testSortedSetRange(t *testing.T, ctx context.Context, z *zset.SortedSet) {
+	require := require.New(t)
+
+	for member, score := range map[string]float64{"a": 5, "b": 3, "c": 2, "d": 2, "e": -1.5, "f": 0} {
+		_, err := z.ZAdd(ctx, member, score)
+		require.NoError(err)
+	}
+
+	collect := func(order kv.Order[float64]) []string {
+		members := []string{}
+		err := z.ZRangeByScore(ctx, order, func(member string, score float64) error {
+			members = append(members, member)
+			return nil
+		})
+		require.NoError(err)
+		return members
+	}
+
+	all := kv.Order[float64]{Min: math.Inf(-1), Max: math.Inf(1)}
+	require.Equal([]string{"e", "f", "c", "d", "b", "a"}, collect(all))
+	all.Reverse = true
+	require.Equal([]string{"a", "b", "d", "c", "f", "e"}, collect(all))
+	require.Equal([]string{"c", "d", "b"}, collect(kv.Order[float64]{Min: 2, Max: 3}))
+	require.Equal([]string{"b", "d", "c"}, collect(kv.Order[float64]{Min: 2, Max: 3, Reverse: true}))
+	require.Equal([]string{"f"}, collect(kv.Order[float64]{Min: math.Copysign(0, -1), Max: 0}))
+
+	n, err := z.ZCount(ctx, 2, 3)
+	require.NoError(err)
+	require.Equal(3, n)
+
+	n, err = z.ZCount(ctx, -1, 1)
+	require.NoError(err)
+	require.Equal(1, n)
+
+	rank, err := z.ZRank(ctx, "c")
+	require.NoError(err)
+	require.Equal(2, rank)
+
+	rank, err = z.ZRevRank(ctx, "c")
+	require.NoError(err)
+	require.Equal(3, rank)
+
+	rank, err = z.ZRevRank(ctx, "a")
+	require.NoError(err)
+	require.Equal(0, rank)
+
+	_, err = z.ZRank(ctx, "missing")
+	require.ErrorIs(err, kv.ErrKeyNotFound)
+
+	_, err = z.ZIncrBy(ctx, "e", 10)
+	require.NoError(err)
+
+	rank, err = z.ZRank(ctx, "e")
+	require.NoError(err)
+	require.Equal(5, rank)
+}
+
+func testSortedSetConcurrent(t *testing.T, ctx context.Context, z *zset.SortedSet) {
+	require := require.New(t)
+
+	const workers, increments = 8, 10
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, workers*increments)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range increments {
+				_, err := z.ZIncrBy(ctx, "member", 1)
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(err)
+	}
+
+	score, err := z.ZScore(ctx, "member")
+	require.NoError(err)
+	require.Equal(float64(workers*increments), score)
+
+	n, err := z.ZCard(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+}
@@ -0,0 +1,53 @@
+package timeseries
+
+import (
+	"time"
+)
+
+// Aggregation selects a value of a bucket.
+type Aggregation int
+
+const (
+	Avg Aggregation = iota
+	Sum
+	Min
+	Max
+	Count
+)
+
+// Bucket is an aggregation of points within [Start, Start + bucket duration).
+type Bucket struct {
+	Start time.Time
+	Count int
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// Avg returns the average value of the bucket points.
+func (b Bucket) Avg() float64 {
+	return b.Sum / float64(b.Count)
+}
+
+// Value returns the aggregated value of the bucket.
+func (b Bucket) Value(agg Aggregation) float64 {
+	switch agg {
+	case Sum:
+		return b.Sum
+	case Min:
+		return b.Min
+	case Max:
+		return b.Max
+	case Count:
+		return float64(b.Count)
+	default:
+		return b.Avg()
+	}
+}
+
+func (b *Bucket) add(v float64) {
+	b.Count++
+	b.Sum += v
+	b.Min = min(b.Min, v)
+	b.Max = max(b.Max, v)
+}
@@ -0,0 +1,228 @@
+// Package timeseries implements storage of numeric time series on top of ordered key-value stores.
+package timeseries
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/royalcat/kv"
+)
+
+var (
+	ErrInvalidSeries = errors.New("series name must be non-empty and must not contain zero bytes")
+	ErrInvalidBucket = errors.New("bucket duration must be positive")
+	ErrInvalidTime   = errors.New("time must be non-zero and representable in unix nanoseconds")
+)
+
+// minTime and maxTime bound times which are stored as int64 unix nanoseconds.
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+// Store is a store capable of holding time series.
+type Store interface {
+	kv.Store[string, []byte]
+	kv.StoreOrdered[string, []byte]
+}
+
+// Point is a single value of a series.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeries stores points of multiple series, every point is stored under its own key
+// ordered by series and time, so time windows are read with a single ordered range.
+type TimeSeries struct {
+	store  Store
+	prefix string
+}
+
+// New creates a time series storage with the given name in the store.
+func New(store Store, name string) *TimeSeries {
+	return &TimeSeries{
+		store:  store,
+		prefix: name + "/",
+	}
+}
+
+// Add writes the points to the series, a point with the same time is overwritten.
+// It returns ErrInvalidTime without writing any point if a time of a point is invalid.
+func (ts *TimeSeries) Add(ctx context.Context, series string, points ...Point) error {
+	if err := validateSeries(series); err != nil {
+		return err
+	}
+	for _, p := range points {
+		if err := validateTime(p.Time); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range points {
+		err := ts.store.Set(ctx, ts.pointKey(series, p.Time), encodeValue(p.Value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Range iterates over points of the series with time in the half-open window [from, to) ordered by time.
+// A zero from or to means the window is unbounded on that side.
+func (ts *TimeSeries) Range(ctx context.Context, series string, from, to time.Time, iter func(p Point) error) error {
+	if err := validateSeries(series); err != nil {
+		return err
+	}
+
+	return ts.store.RangeOrdered(ctx, ts.window(series, from, to), func(k string, v []byte) error {
+		return iter(Point{
+			Time:  decodeTime(k[len(k)-8:]),
+			Value: decodeValue(v),
+		})
+	})
+}
+
+// Aggregate groups points of the series in the window [from, to) into buckets of the given duration
+// and returns non-empty buckets ordered by time. Buckets are aligned to the zero time, see [time.Time.Truncate].
+func (ts *TimeSeries) Aggregate(ctx context.Context, series string, from, to time.Time, bucket time.Duration) ([]Bucket, error) {
+	if bucket <= 0 {
+		return nil, ErrInvalidBucket
+	}
+
+	buckets := []Bucket{}
+	err := ts.Range(ctx, series, from, to, func(p Point) error {
+		start := p.Time.Truncate(bucket)
+		if len(buckets) == 0 || !buckets[len(buckets)-1].Start.Equal(start) {
+			buckets = append(buckets, Bucket{
+				Start: start,
+				Min:   math.Inf(1),
+				Max:   math.Inf(-1),
+			})
+		}
+		buckets[len(buckets)-1].add(p.Value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// Downsample aggregates points of the src series in the window [from, to) into buckets
+// and writes one point per bucket at the bucket start to the dst series.
+func (ts *TimeSeries) Downsample(ctx context.Context, src, dst string, from, to time.Time, bucket time.Duration, agg Aggregation) error {
+	if err := validateSeries(dst); err != nil {
+		return err
+	}
+
+	buckets, err := ts.Aggregate(ctx, src, from, to, bucket)
+	if err != nil {
+		return err
+	}
+
+	points := make([]Point, 0, len(buckets))
+	for _, b := range buckets {
+		points = append(points, Point{Time: b.Start, Value: b.Value(agg)})
+	}
+	return ts.Add(ctx, dst, points...)
+}
+
+// retentionBatch is the number of keys collected before deleting them,
+// keys are not deleted during iteration, as some stores don't allow writes inside a range.
+const retentionBatch = 1000
+
+// DeleteBefore deletes points of the series older than the given time and returns the number of deleted points.
+// A zero time is rejected with ErrInvalidTime, as it would mean an unbounded window deleting the whole series.
+func (ts *TimeSeries) DeleteBefore(ctx context.Context, series string, before time.Time) (int, error) {
+	if err := validateSeries(series); err != nil {
+		return 0, err
+	}
+	if err := validateTime(before); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for {
+		keys := make([]string, 0, retentionBatch)
+		err := ts.store.RangeOrdered(ctx, ts.window(series, time.Time{}, before), func(k string, _ []byte) error {
+			keys = append(keys, k)
+			if len(keys) == retentionBatch {
+				return errBatchFull
+			}
+			return nil
+		})
+		if err != nil && err != errBatchFull {
+			return deleted, err
+		}
+
+		for _, k := range keys {
+			err := ts.store.Delete(ctx, k)
+			if err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if len(keys) < retentionBatch {
+			return deleted, nil
+		}
+	}
+}
+
+var errBatchFull = errors.New("batch is full")
+
+func (ts *TimeSeries) window(series string, from, to time.Time) kv.Order[string] {
+	order := kv.Order[string]{
+		Min: ts.prefix + series + "\x00",
+		// the next key after all points of the series
+		Max: ts.prefix + series + "\x01",
+	}
+	if !from.IsZero() {
+		order.Min = ts.pointKey(series, from)
+	}
+	if !to.IsZero() {
+		order.Max = ts.pointKey(series, to)
+	}
+	return order
+}
+
+func (ts *TimeSeries) pointKey(series string, t time.Time) string {
+	return ts.prefix + series + "\x00" + string(encodeTime(t))
+}
+
+func validateSeries(series string) error {
+	if series == "" || strings.IndexByte(series, 0) >= 0 {
+		return ErrInvalidSeries
+	}
+	return nil
+}
+
+func validateTime(t time.Time) error {
+	if t.IsZero() || t.Before(minTime) || t.After(maxTime) {
+		return ErrInvalidTime
+	}
+	return nil
+}
+
+// encodeTime encodes the time with nanosecond precision preserving the order of times in the order of bytes.
+func encodeTime(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano())^(1<<63))
+}
+
+func decodeTime(data string) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64([]byte(data))^(1<<63)))
+}
+
+func encodeValue(v float64) []byte {
+	return binary.BigEndian.AppendUint64(nil, math.Float64bits(v))
+}
+
+func decodeValue(data []byte) float64 {
+	return math.Float64frombits(binary.BigEndian.Uint64(data))
+}
@@ -1,5 +1,11 @@
 package kv
 
+import "errors"
+
+// ErrConflict is returned when a transaction can't be committed because of a concurrent modification.
+// The transaction can be retried.
+var ErrConflict = errors.New("transaction conflict")
+
 type TransactionalStore[K, V any] interface {
 	Transaction(update bool) (Store[K, V], error)
 }
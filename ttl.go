@@ -0,0 +1,23 @@
+package kv
+
+import (
+	"context"
+	"time"
+)
+
+// StoreTTL is an optional interface for stores able to expire keys natively.
+type StoreTTL[K, V any] interface {
+	// SetWithTTL stores the value for the key, which expires after ttl.
+	// A non-positive ttl stores the value without expiration.
+	SetWithTTL(ctx context.Context, k K, v V, ttl time.Duration) error
+}
+
+// SetWithTTL stores the value for the key, which expires after ttl.
+// It uses [StoreTTL] when the store implements it and falls back to [Store.Set] otherwise,
+// so the value is kept until it is deleted.
+func SetWithTTL[K, V any](ctx context.Context, s Store[K, V], k K, v V, ttl time.Duration) error {
+	if st, ok := s.(StoreTTL[K, V]); ok {
+		return st.SetWithTTL(ctx, k, v, ttl)
+	}
+	return s.Set(ctx, k, v)
+}
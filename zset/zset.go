@@ -0,0 +1,259 @@
+// Package zset implements sorted sets (leaderboards) on top of ordered transactional key-value stores.
+package zset
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/royalcat/kv"
+)
+
+var ErrInvalidScore = errors.New("score must not be NaN")
+
+// Store is a store capable of holding sorted sets.
+type Store interface {
+	kv.Store[string, []byte]
+	kv.StoreOrdered[string, []byte]
+	kv.TransactionalStore[string, []byte]
+}
+
+// SortedSet is a set of unique members ordered by their scores, members with equal scores are ordered lexicographically.
+//
+// Every member is stored as a member → score record and a score-ordered index entry,
+// both are updated atomically in a single transaction.
+type SortedSet struct {
+	store   Store
+	members string
+	scores  string
+}
+
+// New creates a sorted set with the given name in the store, multiple sets can share the same store.
+func New(store Store, name string) *SortedSet {
+	return &SortedSet{
+		store:   store,
+		members: name + "/m/",
+		scores:  name + "/s/",
+	}
+}
+
+// ZAdd sets the score of the member, it returns true if the member was added and false if its score was updated.
+func (z *SortedSet) ZAdd(ctx context.Context, member string, score float64) (bool, error) {
+	if math.IsNaN(score) {
+		return false, ErrInvalidScore
+	}
+
+	var added bool
+	err := z.update(ctx, func(tx kv.Store[string, []byte]) error {
+		old, ok, err := z.score(ctx, tx, member)
+		if err != nil {
+			return err
+		}
+		added = !ok
+
+		return z.set(ctx, tx, member, old, ok, score)
+	})
+	return added, err
+}
+
+// ZIncrBy increments the score of the member by delta and returns the new score.
+// A missing member is added with the score equal to delta.
+func (z *SortedSet) ZIncrBy(ctx context.Context, member string, delta float64) (float64, error) {
+	var score float64
+	err := z.update(ctx, func(tx kv.Store[string, []byte]) error {
+		old, ok, err := z.score(ctx, tx, member)
+		if err != nil {
+			return err
+		}
+
+		score = old + delta
+		if math.IsNaN(score) {
+			return ErrInvalidScore
+		}
+
+		return z.set(ctx, tx, member, old, ok, score)
+	})
+	return score, err
+}
+
+// ZScore returns the score of the member or kv.ErrKeyNotFound.
+func (z *SortedSet) ZScore(ctx context.Context, member string) (float64, error) {
+	score, ok, err := z.score(ctx, z.store, member)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, kv.ErrKeyNotFound
+	}
+	return score, nil
+}
+
+// ZRem removes the member, it returns false if the member was not in the set.
+func (z *SortedSet) ZRem(ctx context.Context, member string) (bool, error) {
+	var removed bool
+	err := z.update(ctx, func(tx kv.Store[string, []byte]) error {
+		score, ok, err := z.score(ctx, tx, member)
+		if err != nil || !ok {
+			return err
+		}
+		removed = true
+
+		err = tx.Delete(ctx, z.indexKey(score, member))
+		if err != nil {
+			return err
+		}
+		return tx.Delete(ctx, z.members+member)
+	})
+	return removed, err
+}
+
+// ZRank returns the zero-based position of the member ordered by ascending score, or kv.ErrKeyNotFound.
+// It iterates over all preceding members, so it's linear in the rank.
+func (z *SortedSet) ZRank(ctx context.Context, member string) (int, error) {
+	score, ok, err := z.score(ctx, z.store, member)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, kv.ErrKeyNotFound
+	}
+
+	return z.count(ctx, kv.Order[string]{Min: z.scores, Max: z.indexKey(score, member)})
+}
+
+// ZRevRank returns the zero-based position of the member ordered by descending score, or kv.ErrKeyNotFound.
+// It iterates over all preceding members, so it's linear in the rank.
+func (z *SortedSet) ZRevRank(ctx context.Context, member string) (int, error) {
+	score, ok, err := z.score(ctx, z.store, member)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, kv.ErrKeyNotFound
+	}
+
+	return z.count(ctx, kv.Order[string]{Min: z.indexKey(score, member) + "\x00", Max: z.scoresEnd()})
+}
+
+// ZRangeByScore iterates over members with scores in the range of the order.
+//
+// Unlike the general [kv.Order] semantic, both score bounds are inclusive and always applied,
+// use math.Inf for an unbounded range. Reverse iterates from the highest score.
+func (z *SortedSet) ZRangeByScore(ctx context.Context, order kv.Order[float64], iter kv.Iter[string, float64]) error {
+	r, err := z.scoreRange(order)
+	if err != nil {
+		return err
+	}
+
+	return z.store.RangeOrdered(ctx, r, func(k string, member []byte) error {
+		return iter(string(member), decodeScore([]byte(k[len(z.scores):])))
+	})
+}
+
+// ZCount returns the number of members with scores between min and max inclusive.
+func (z *SortedSet) ZCount(ctx context.Context, min, max float64) (int, error) {
+	r, err := z.scoreRange(kv.Order[float64]{Min: min, Max: max})
+	if err != nil {
+		return 0, err
+	}
+
+	return z.count(ctx, r)
+}
+
+// ZCard returns the number of members in the set.
+func (z *SortedSet) ZCard(ctx context.Context) (int, error) {
+	return kv.Count(ctx, kv.Store[string, []byte](z.store), z.members)
+}
+
+func (z *SortedSet) score(ctx context.Context, s kv.Store[string, []byte], member string) (float64, bool, error) {
+	data, err := s.Get(ctx, z.members+member)
+	if errors.Is(err, kv.ErrKeyNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return decodeScore(data), true, nil
+}
+
+func (z *SortedSet) set(ctx context.Context, tx kv.Store[string, []byte], member string, old float64, exists bool, score float64) error {
+	if exists {
+		err := tx.Delete(ctx, z.indexKey(old, member))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tx.Set(ctx, z.members+member, encodeScore(score))
+	if err != nil {
+		return err
+	}
+	return tx.Set(ctx, z.indexKey(score, member), []byte(member))
+}
+
+func (z *SortedSet) count(ctx context.Context, r kv.Order[string]) (int, error) {
+	n := 0
+	err := z.store.RangeOrdered(ctx, r, func(string, []byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+func (z *SortedSet) scoreRange(order kv.Order[float64]) (kv.Order[string], error) {
+	if math.IsNaN(order.Min) || math.IsNaN(order.Max) {
+		return kv.Order[string]{}, ErrInvalidScore
+	}
+
+	r := kv.Order[string]{
+		Min:     z.scores + string(encodeScore(order.Min)),
+		Max:     z.scoresEnd(),
+		Reverse: order.Reverse,
+	}
+	if order.Max < math.Inf(1) {
+		// any member with max score is less than the next score
+		r.Max = z.scores + string(encodeScore(math.Nextafter(order.Max, math.Inf(1))))
+	}
+	return r, nil
+}
+
+func (z *SortedSet) scoresEnd() string {
+	// the next key after all index entries, as '/' + 1 is '0'
+	return z.scores[:len(z.scores)-1] + "0"
+}
+
+func (z *SortedSet) indexKey(score float64, member string) string {
+	return z.scores + string(encodeScore(score)) + member
+}
+
+// update runs the function in an update transaction retried on conflicts,
+// its writes are applied only if it succeeds.
+func (z *SortedSet) update(ctx context.Context, f func(tx kv.Store[string, []byte]) error) error {
+	return kv.Update(ctx, kv.Store[string, []byte](z.store), f)
+}
+
+// encodeScore encodes the score preserving the order of floats in the order of bytes.
+func encodeScore(score float64) []byte {
+	if score == 0 {
+		// negative zero is equal to zero
+		score = 0
+	}
+	bits := math.Float64bits(score)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	return binary.BigEndian.AppendUint64(nil, bits)
+}
+
+func decodeScore(data []byte) float64 {
+	bits := binary.BigEndian.Uint64(data)
+	if bits&(1<<63) != 0 {
+		bits &^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits)
+}